@@ -13,11 +13,22 @@ import (
 	"github.com/EthereumHD/Scan/src/api/block_query"
 	"github.com/EthereumHD/Scan/src/api/block_query/block_number"
 	"github.com/EthereumHD/Scan/src/api/block_query/get_block_by_height"
+	"github.com/EthereumHD/Scan/src/api/chart"
+	"github.com/EthereumHD/Scan/src/api/contract/get_info"
+	"github.com/EthereumHD/Scan/src/api/label"
+	"github.com/EthereumHD/Scan/src/api/log_query"
 	"github.com/EthereumHD/Scan/src/api/mining"
 	"github.com/EthereumHD/Scan/src/api/mining/get_mined_block_by_addr_and_date"
+	"github.com/EthereumHD/Scan/src/api/mortgage"
+	"github.com/EthereumHD/Scan/src/api/nft"
 	"github.com/EthereumHD/Scan/src/api/poc/get_balance"
 	"github.com/EthereumHD/Scan/src/api/poc/get_exchange_rate"
 	"github.com/EthereumHD/Scan/src/api/poc/get_summary"
+	"github.com/EthereumHD/Scan/src/api/poc/get_summary_history"
+	"github.com/EthereumHD/Scan/src/api/poc/proof"
+	"github.com/EthereumHD/Scan/src/api/search"
+	"github.com/EthereumHD/Scan/src/api/sync/get_status"
+	"github.com/EthereumHD/Scan/src/api/token"
 	"github.com/EthereumHD/Scan/src/api/transaction"
 	"github.com/EthereumHD/Scan/src/api/transaction/get_addr_pending"
 	"github.com/EthereumHD/Scan/src/api/transaction/get_hash_pending"
@@ -33,6 +44,7 @@ var (
 	GetBlockByHeight = get_block_by_height.Main
 	GetBlocks        = block_query.Get_Blocks
 	GetBlockByHash   = block_query.Get_by_hash
+	GetReorgs        = block_query.Get_reorgs
 
 	//transaction
 	GetTransactionByHash = get_transaction_by_hash.Main
@@ -43,13 +55,67 @@ var (
 	GetAddrPending       = get_addr_pending.Main
 	GetHashPending       = get_hash_pending.Main
 
+	//log
+	QueryLogs = log_query.Query
+
+	//contract
+	GetContractInfo = get_info.Main
+
+	//token
+	GetTokenList           = token.Get_list
+	GetTokenTransfers      = token.Get_transfers
+	GetTokenHolders        = token.Get_holders
+	GetTokenBalancesByAddr = token.Get_balances_by_addr
+
+	//nft
+	GetNftByOwner   = nft.Get_by_owner
+	GetNftToken     = nft.Get_token
+	GetNftTransfers = nft.Get_transfers
+
+	//mortgage
+	GetStake         = mortgage.Get_stake
+	GetStakeHistory  = mortgage.Get_history
+	GetTopStakers    = mortgage.Get_top_stakers
+	GetTotalMortgage = mortgage.Get_total
+
+	GetCompliance          = mortgage.Get_compliance
+	GetUnderCollateralised = mortgage.Get_under_collateralised
+
 	//mining
 	GetMinedBlocks             = mining.Get_mined_block_by_addr
 	GetAddrMiningRewards       = mining.Main
 	GetMinedblockByAddrAndDate = get_mined_block_by_addr_and_date.Main
+	GetMinerPerformance        = mining.Get_miner_performance
+	GetTopMiners               = mining.Get_top_miners
 
 	//poc
-	GetExchangeRate = get_exchange_rate.Main
-	GetBalance      = get_balance.Main
-	GetSummary      = get_summary.Main
+	GetExchangeRate   = get_exchange_rate.Main
+	GetBalance        = get_balance.Main
+	GetSummary        = get_summary.Main
+	GetSummaryHistory = get_summary_history.Main
+
+	GetDeadlinesByMiner  = proof.Get_deadlines_by_miner
+	GetDeadlineHistory   = proof.Get_deadline_history
+	GetDeadlineSummary   = proof.Get_deadline_summary
+	GetScoopDistribution = proof.Get_scoop_distribution
+
+	//chart
+	GetChartTxCount         = chart.Get_tx_count
+	GetChartBlockTime       = chart.Get_block_time
+	GetChartDifficulty      = chart.Get_difficulty
+	GetChartActiveAddresses = chart.Get_active_addresses
+	GetChartFees            = chart.Get_fees
+
+	//label
+	GetLabel     = label.Get_label
+	GetLabels    = label.Get_labels
+	SetLabel     = label.Set_label
+	DeleteLabel  = label.Delete_label
+	ImportLabels = label.Import_labels
+
+	//search
+	Search = search.Main
+
+	//sync
+	GetSyncStatus = get_status.Main
 )
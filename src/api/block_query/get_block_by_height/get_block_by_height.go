@@ -10,6 +10,7 @@ import (
 	"go-web3/providers"
 	"math/big"
 	"qoobing.com/utillib.golang/log"
+	"strconv"
 )
 
 type Input struct {
@@ -42,54 +43,65 @@ func Main(cc echo.Context) error {
 		return c.RESULT_PARAMETER_ERROR(err.Error())
 	}
 
-	//get transcation from chain
 	webthree := web3.NewWeb3(providers.NewHTTPProvider(config.Config().Gate, config.Config().TimeOut.RPCTimeOut, false))
-	chain_block, err := webthree.Eth.GetBlockByNumber(big.NewInt(input.Height), false)
-	if err != nil {
-		if err.Error() == _const.EMPTY_RSP {
-			log.Debugf("GetBlockByNumber:%d from chain is NULL", input.Height)
-			return c.RESULT_ERROR(_const.BLOCK_OR_TRANS_NOT_EXIST, err.Error())
-		}
-		return c.RESULT_ERROR(_const.ERR_RPC_ERROR, err.Error())
-	}
 
-	poc, err := webthree.Eth.GetBlockPocByNumber(big.NewInt(input.Height))
-	if err != nil {
-		if err.Error() == _const.EMPTY_RSP {
-			log.Debugf("GetBlockPocByNumber:%d from chain is NULL", input.Height)
-			return c.RESULT_ERROR(_const.BLOCK_OR_TRANS_NOT_EXIST, err.Error())
-		}
-		return c.RESULT_ERROR(_const.ERR_RPC_ERROR, err.Error())
+	//get block from databases, the chain only for the ones not indexed yet
+	databases_block, err := (&model.Block{}).FindBlockByHeight(c.Mysql(), input.Height)
+	if err != nil && err.Error() != _const.DATA_NOT_EXIST {
+		return c.RESULT_ERROR(_const.ERR_DATABASE_ERROR, err.Error())
 	}
 
-	databases_block, err := (&model.Block{}).FindBlockByHeight(c.Mysql(), input.Height)
-	if err != nil {
-		if err.Error() == _const.DATA_NOT_EXIST {
-			log.Debugf("FindBlockByHeight:%d from databases is NULL", input.Height)
-			return c.RESULT_ERROR(_const.BLOCK_OR_TRANS_NOT_EXIST, err.Error())
+	if err == nil && databases_block.HasDetail() {
+		output.BlockDetail = databases_block.ToBlockDetail()
+	} else {
+		chain_block, err := webthree.Eth.GetBlockByNumber(big.NewInt(input.Height), false)
+		if err != nil {
+			if err.Error() == _const.EMPTY_RSP {
+				log.Debugf("GetBlockByNumber:%d from chain is NULL", input.Height)
+				return c.RESULT_ERROR(_const.BLOCK_OR_TRANS_NOT_EXIST, err.Error())
+			}
+			return c.RESULT_ERROR(_const.ERR_RPC_ERROR, err.Error())
 		}
 
-		return c.RESULT_ERROR(_const.ERR_DATABASE_ERROR, err.Error())
+		output.BlockDetail = model.BlockDetail{
+			Height:         input.Height,
+			Timestamp:      chain_block.Timestamp.Int64(),
+			Transactions:   int64(len(chain_block.Transactions)),
+			Hash:           chain_block.Hash,
+			ParentHash:     chain_block.ParentHash,
+			Miner:          chain_block.Miner,
+			Difficult:      chain_block.Difficulty.String(),
+			TotalDifficult: chain_block.TotalDifficult.String(),
+			Size:           chain_block.Size.Int64(),
+			GasUsed:        chain_block.GasUsed.String(),
+			GasLimit:       chain_block.GasLimit.String(),
+			Nonce:          chain_block.Nonce.String(),
+			BlockReward:    chain_block.Reward.String(),
+			BlockFees:      chain_block.TxFees.String(),
+			ExtraData:      chain_block.ExtraData,
+		}
+		if databases_block.F_hash == chain_block.Hash {
+			output.BlockReward = databases_block.F_reward
+			output.BlockFees = databases_block.F_fees
+		}
 	}
 
-	output.BlockDetail = model.BlockDetail{
-		Height:         input.Height,
-		Timestamp:      chain_block.Timestamp.Int64(),
-		Transactions:   int64(len(chain_block.Transactions)),
-		Hash:           chain_block.Hash,
-		ParentHash:     chain_block.ParentHash,
-		Miner:          chain_block.Miner,
-		Difficult:      chain_block.Difficulty.String(),
-		TotalDifficult: chain_block.TotalDifficult.String(),
-		Size:           chain_block.Size.Int64(),
-		GasUsed:        chain_block.GasUsed.String(),
-		GasLimit:       chain_block.GasLimit.String(),
-		Nonce:          chain_block.Nonce.String(),
-		BlockReward:    databases_block.F_reward,
-		BlockFees:      databases_block.F_fees,
-		ExtraData:      chain_block.ExtraData,
-		DeadLine:       poc.Deadline.String(),
-		Scoop:          poc.ScoopNumber.String(),
+	//poc from databases, the chain only for the blocks not indexed yet
+	databases_poc, err := (&model.BlockPoc{}).FindBlockPocByHash(c.Mysql(), output.Hash)
+	if err == nil {
+		output.DeadLine = databases_poc.F_deadline
+		output.Scoop = strconv.FormatInt(databases_poc.F_scoop, 10)
+	} else {
+		poc, err := webthree.Eth.GetBlockPocByNumber(big.NewInt(input.Height))
+		if err != nil {
+			if err.Error() == _const.EMPTY_RSP {
+				log.Debugf("GetBlockPocByNumber:%d from chain is NULL", input.Height)
+				return c.RESULT_ERROR(_const.BLOCK_OR_TRANS_NOT_EXIST, err.Error())
+			}
+			return c.RESULT_ERROR(_const.ERR_RPC_ERROR, err.Error())
+		}
+		output.DeadLine = poc.Deadline.String()
+		output.Scoop = poc.ScoopNumber.String()
 	}
 	//todo extradat scopp check
 
@@ -7,6 +7,7 @@ import (
 	. "github.com/EthereumHD/Scan/src/const"
 	. "github.com/EthereumHD/Scan/src/model"
 	"qoobing.com/utillib.golang/log"
+	"strings"
 )
 
 type InputReq struct {
@@ -25,6 +26,7 @@ type BlockInfo struct {
 	BlockNumber int64  `json:"block_number"`
 	Timestamp   int64  `json:"timestamp"`
 	BlockMiner   string `json:"block_miner"`
+	MinerLabel  string `json:"miner_label,omitempty"` //矿工的地址标签
 	BlockReward string `json:"block_reward"`
 	BlockFees   string `json:"block_fees"`
 	GasUsed     string `json:"gas_used"`
@@ -72,11 +74,21 @@ func Get_Blocks(cc echo.Context) error {
 		log.Debugf("GetRecentBlocks error:%s", err.Error())
 		return c.RESULT_ERROR(GET_BLOCKS_ERROR, fmt.Sprintf("GetRecentBlocks error:%s", err.Error())) //c.RESULT(rsp)
 	}
+	miners := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		miners = append(miners, block.F_miner)
+	}
+	labels, err := GetAddressLabels(c.Mysql(), miners)
+	if err != nil {
+		log.Debugf("GetAddressLabels error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetAddressLabels error:%s", err.Error()))
+	}
 	//包装参数
 	for _, block := range blocks {
 		var blockInfo BlockInfo
 		blockInfo.BlockNumber = block.F_block
 		blockInfo.BlockMiner = block.F_miner
+		blockInfo.MinerLabel = labels[strings.ToLower(block.F_miner)].F_label
 		blockInfo.BlockReward = block.F_reward
 		blockInfo.Timestamp = block.F_timestamp
 		blockInfo.BlockFees=block.F_fees
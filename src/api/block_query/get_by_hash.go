@@ -10,7 +10,9 @@ import (
 	"github.com/labstack/echo"
 	"go-web3"
 	"go-web3/providers"
+	"math/big"
 	log "qoobing.com/utillib.golang/log"
+	"strconv"
 )
 
 type InputHashReq struct {
@@ -46,25 +48,7 @@ func Get_by_hash(cc echo.Context) error {
 
 	//查询区块
 
-	//get transcation from chain
 	webthree := web3.NewWeb3(providers.NewHTTPProvider(config.Config().Gate, config.Config().TimeOut.RPCTimeOut, false))
-	chain_block, err := webthree.Eth.GetBlockByHash(argc.Hash, false)
-	if err != nil {
-		if err.Error() == EMPTY_RSP {
-			log.Debugf("GetBlockByHash:%d from chain is NULL", argc.Hash)
-			return c.RESULT_ERROR(BLOCK_OR_TRANS_NOT_EXIST, err.Error())
-		}
-		return c.RESULT_ERROR(ERR_RPC_ERROR, err.Error())
-	}
-
-	poc, err := webthree.Eth.GetBlockPocByNumber(chain_block.Number)
-	if err != nil {
-		if err.Error() == EMPTY_RSP {
-			log.Debugf("GetBlockPocByNumber:%d from chain is NULL", chain_block.Number.Int64())
-			return c.RESULT_ERROR(BLOCK_OR_TRANS_NOT_EXIST, err.Error())
-		}
-		return c.RESULT_ERROR(ERR_RPC_ERROR, err.Error())
-	}
 
 	//查询区块,数据库查询
 	blocks, err := GetBlockByHash(c.Mysql(), argc.Hash)
@@ -72,29 +56,57 @@ func Get_by_hash(cc echo.Context) error {
 		log.Debugf("GetBlockByHash error:%s,hash:%s", err.Error(), argc.Hash)
 		return c.RESULT_ERROR(GET_BLOCKS_ERROR, fmt.Sprintf("GetBlockByHash error:%s,hash:%s", err.Error(), argc.Hash)) //c.RESULT(rsp)
 	}
-	//包装参数
-	if len(blocks) == 0 {
-		log.Debugf("the block not exist,hash:%s", argc.Hash)
-		return c.RESULT_ERROR(BLOCK_OR_TRANS_NOT_EXIST, fmt.Sprintf("the block not exist,hash:%s", argc.Hash))
+	if len(blocks) > 0 && blocks[0].HasDetail() {
+		rsp.BlockDetail = blocks[0].ToBlockDetail()
+	} else {
+		//not indexed yet, get block from chain
+		chain_block, err := webthree.Eth.GetBlockByHash(argc.Hash, false)
+		if err != nil {
+			if err.Error() == EMPTY_RSP {
+				log.Debugf("GetBlockByHash:%s from chain is NULL", argc.Hash)
+				return c.RESULT_ERROR(BLOCK_OR_TRANS_NOT_EXIST, err.Error())
+			}
+			return c.RESULT_ERROR(ERR_RPC_ERROR, err.Error())
+		}
+
+		rsp.Height = chain_block.Number.Int64()
+		rsp.Hash = chain_block.Hash
+		rsp.Transactions = int64(len(chain_block.Transactions))
+		rsp.Timestamp = chain_block.Timestamp.Int64()
+		rsp.BlockReward = chain_block.Reward.String()
+		rsp.BlockFees = chain_block.TxFees.String()
+		rsp.ExtraData = chain_block.ExtraData
+		rsp.GasLimit = chain_block.GasLimit.String()
+		rsp.GasUsed = chain_block.GasUsed.String()
+		rsp.Miner = chain_block.Miner
+		rsp.Nonce = chain_block.Nonce.String()
+		rsp.ParentHash = chain_block.ParentHash
+		rsp.Size = chain_block.Size.Int64()
+		rsp.TotalDifficult = chain_block.TotalDifficult.String()
+		rsp.Difficult = chain_block.Difficulty.String()
+		if len(blocks) > 0 {
+			rsp.BlockReward = blocks[0].F_reward
+			rsp.BlockFees = blocks[0].F_fees
+		}
+	}
+
+	//poc from databases, the chain only for the blocks not indexed yet
+	databases_poc, err := (&BlockPoc{}).FindBlockPocByHash(c.Mysql(), rsp.Hash)
+	if err == nil {
+		rsp.DeadLine = databases_poc.F_deadline
+		rsp.Scoop = strconv.FormatInt(databases_poc.F_scoop, 10)
+	} else {
+		poc, err := webthree.Eth.GetBlockPocByNumber(big.NewInt(rsp.Height))
+		if err != nil {
+			if err.Error() == EMPTY_RSP {
+				log.Debugf("GetBlockPocByNumber:%d from chain is NULL", rsp.Height)
+				return c.RESULT_ERROR(BLOCK_OR_TRANS_NOT_EXIST, err.Error())
+			}
+			return c.RESULT_ERROR(ERR_RPC_ERROR, err.Error())
+		}
+		rsp.DeadLine = poc.Deadline.String()
+		rsp.Scoop = poc.ScoopNumber.String()
 	}
-	block := blocks[0]
-	rsp.Height = block.F_block
-	rsp.Hash = chain_block.Hash
-	rsp.Transactions = block.F_txn
-	rsp.Timestamp = chain_block.Timestamp.Int64()
-	rsp.BlockReward = block.F_reward
-	rsp.BlockFees = block.F_fees
-	rsp.DeadLine = poc.Deadline.String()
-	rsp.ExtraData = chain_block.ExtraData
-	rsp.GasLimit = chain_block.GasLimit.String()
-	rsp.GasUsed = chain_block.GasUsed.String()
-	rsp.Miner = chain_block.Miner
-	rsp.Nonce = chain_block.Nonce.String()
-	rsp.ParentHash = chain_block.ParentHash
-	rsp.Scoop = poc.ScoopNumber.String()
-	rsp.Size = chain_block.Size.Int64()
-	rsp.TotalDifficult = chain_block.TotalDifficult.String()
-	rsp.Difficult = chain_block.Difficulty.String()
 
 	//返回结果
 	return c.RESULT(rsp)
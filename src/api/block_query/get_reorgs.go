@@ -0,0 +1,88 @@
+package block_query
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+type GetReorgsRsp struct {
+	ErrNo  int         `json:"err_no"`
+	ErrMsg string      `json:"err_msg"`
+	Count  int64       `json:"count"` //重组次数
+	Reorgs []ReorgInfo `json:"reorgs"`
+}
+
+type ReorgInfo struct {
+	Ancestor       int64    `json:"ancestor"`        //共同祖先高度
+	AncestorHash   string   `json:"ancestor_hash"`   //共同祖先hash
+	Depth          int64    `json:"depth"`           //回滚的区块数
+	OldHead        int64    `json:"old_head"`        //回滚前的最高区块
+	OldHeadHash    string   `json:"old_head_hash"`   //回滚前的最高区块hash
+	NewHead        int64    `json:"new_head"`        //发现重组的新链区块
+	NewHeadHash    string   `json:"new_head_hash"`   //发现重组的新链区块hash
+	OrphanedHashes []string `json:"orphaned_hashes"` //回滚的区块hash，从高到低
+	Timestamp      int64    `json:"timestamp"`       //发现时间
+}
+
+func Get_reorgs(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := GetReorgsRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Reorgs: []ReorgInfo{},
+	}
+
+	argc := new(InputReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_reorgs: %+v", argc)
+
+	//检查参数
+	if argc.PageIndex < 1 || argc.PageSize <= 0 {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询重组记录,数据库查询
+	count, err := GetReorgNum(c.Mysql())
+	if err != nil {
+		log.Debugf("GetReorgNum error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetReorgNum error:%s", err.Error()))
+	}
+	rsp.Count = count
+
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	reorgs, err := GetRecentReorgs(c.Mysql(), offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetRecentReorgs error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetRecentReorgs error:%s", err.Error()))
+	}
+
+	//包装参数
+	for _, reorg := range reorgs {
+		rsp.Reorgs = append(rsp.Reorgs, ReorgInfo{
+			Ancestor:       reorg.F_ancestor,
+			AncestorHash:   reorg.F_ancestor_hash,
+			Depth:          reorg.F_depth,
+			OldHead:        reorg.F_old_head,
+			OldHeadHash:    reorg.F_old_head_hash,
+			NewHead:        reorg.F_new_head,
+			NewHeadHash:    reorg.F_new_head_hash,
+			OrphanedHashes: reorg.OrphanedHashes(),
+			Timestamp:      reorg.F_timestamp,
+		})
+	}
+	//返回结果
+	return c.RESULT(rsp)
+}
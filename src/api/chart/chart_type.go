@@ -0,0 +1,104 @@
+package chart
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	MAX_CHART_DAYS = 366 //最多一年
+	DATE_FORMAT    = "2006-01-02"
+)
+
+// InputChartReq the UTC days [start_date, end_date], the last 30 days by default
+type InputChartReq struct {
+	StartDate string `json:"start_date" form:"start_date"` //2006-01-02，UTC
+	EndDate   string `json:"end_date" form:"end_date"`     //2006-01-02，UTC，包含当天
+}
+
+type TxCountPoint struct {
+	Date    string `json:"date"`
+	TxCount int64  `json:"tx_count"`
+	Blocks  int64  `json:"blocks"`
+}
+
+type BlockTimePoint struct {
+	Date         string  `json:"date"`
+	AvgBlockTime float64 `json:"avg_block_time"` //秒，只有一个块时为0
+	Blocks       int64   `json:"blocks"`
+}
+
+type DifficultyPoint struct {
+	Date            string  `json:"date"`
+	AvgDifficulty   float64 `json:"avg_difficulty"`
+	NetworkCapacity int64   `json:"network_capacity"` //由平均难度估算(byte)
+}
+
+type AddressPoint struct {
+	Date            string `json:"date"`
+	ActiveAddresses int64  `json:"active_addresses"`
+	NewAddresses    int64  `json:"new_addresses"`
+}
+
+type FeesPoint struct {
+	Date         string  `json:"date"`
+	Fees         string  `json:"fees"`
+	Reward       string  `json:"reward"`
+	GasUsedRatio float64 `json:"gas_used_ratio"`
+}
+
+type OutputTxCountRsp struct {
+	ErrNo  int            `json:"err_no"`
+	ErrMsg string         `json:"err_msg"`
+	Points []TxCountPoint `json:"points"`
+}
+
+type OutputBlockTimeRsp struct {
+	ErrNo  int              `json:"err_no"`
+	ErrMsg string           `json:"err_msg"`
+	Points []BlockTimePoint `json:"points"`
+}
+
+type OutputDifficultyRsp struct {
+	ErrNo  int               `json:"err_no"`
+	ErrMsg string            `json:"err_msg"`
+	Points []DifficultyPoint `json:"points"`
+}
+
+type OutputAddressRsp struct {
+	ErrNo  int            `json:"err_no"`
+	ErrMsg string         `json:"err_msg"`
+	Points []AddressPoint `json:"points"`
+}
+
+type OutputFeesRsp struct {
+	ErrNo  int         `json:"err_no"`
+	ErrMsg string      `json:"err_msg"`
+	Points []FeesPoint `json:"points"`
+}
+
+// dateRange the unix seconds [start, end) of the request days
+func dateRange(argc *InputChartReq) (start int64, end int64, err error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to := today.AddDate(0, 0, -29), today
+	if argc.StartDate != "" {
+		if from, err = time.ParseInLocation(DATE_FORMAT, argc.StartDate, time.UTC); err != nil {
+			return 0, 0, errors.New(argc.StartDate + " date error")
+		}
+	}
+	if argc.EndDate != "" {
+		if to, err = time.ParseInLocation(DATE_FORMAT, argc.EndDate, time.UTC); err != nil {
+			return 0, 0, errors.New(argc.EndDate + " date error")
+		}
+	}
+	to = to.AddDate(0, 0, 1)
+	if !from.Before(to) || to.Sub(from) > MAX_CHART_DAYS*24*time.Hour {
+		return 0, 0, errors.New("date range error")
+	}
+
+	return from.Unix(), to.Unix(), nil
+}
+
+func toDate(day int64) string {
+	return time.Unix(day, 0).UTC().Format(DATE_FORMAT)
+}
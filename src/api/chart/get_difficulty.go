@@ -0,0 +1,59 @@
+package chart
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/metrics"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+// Get_difficulty the average difficulty of each UTC day and the network capacity it implies
+func Get_difficulty(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputDifficultyRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Points: []DifficultyPoint{},
+	}
+
+	argc := new(InputChartReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_difficulty: %+v", argc)
+
+	//检查参数
+	start, end, err := dateRange(argc)
+	if err != nil {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, err.Error())
+	}
+
+	//查询数据库
+	days, err := GetDailyStats(c.Mysql(), start, end)
+	if err != nil {
+		log.Debugf("GetDailyStats error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetDailyStats error:%s", err.Error()))
+	}
+
+	//包装参数
+	for _, day := range days {
+		rsp.Points = append(rsp.Points, DifficultyPoint{
+			Date:            toDate(day.F_day),
+			AvgDifficulty:   day.F_avg_difficulty,
+			NetworkCapacity: int64(metrics.CapacityOf(day.F_avg_difficulty)),
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
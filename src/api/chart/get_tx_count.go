@@ -0,0 +1,58 @@
+package chart
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+// Get_tx_count the transactions and blocks of each UTC day
+func Get_tx_count(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputTxCountRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Points: []TxCountPoint{},
+	}
+
+	argc := new(InputChartReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_tx_count: %+v", argc)
+
+	//检查参数
+	start, end, err := dateRange(argc)
+	if err != nil {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, err.Error())
+	}
+
+	//查询数据库
+	days, err := GetDailyStats(c.Mysql(), start, end)
+	if err != nil {
+		log.Debugf("GetDailyStats error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetDailyStats error:%s", err.Error()))
+	}
+
+	//包装参数
+	for _, day := range days {
+		rsp.Points = append(rsp.Points, TxCountPoint{
+			Date:    toDate(day.F_day),
+			TxCount: day.F_txs,
+			Blocks:  day.F_blocks,
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,85 @@
+package get_info
+
+import (
+	"github.com/EthereumHD/EhdChain/common"
+	"github.com/EthereumHD/EhdChain/crypto"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	"github.com/EthereumHD/Scan/src/config"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"go-web3"
+	"go-web3/eth/block"
+	"go-web3/providers"
+	"qoobing.com/utillib.golang/log"
+)
+
+type Input struct {
+	Addr string `json:"addr" form:"addr" validate:"required"`
+}
+
+type Output struct {
+	ErrNo     int    `json:"err_no"`
+	ErrMsg    string `json:"err_msg"`
+	Address   string `json:"address"`
+	Creator   string `json:"creator"`   //部署者，创世块中的合约为空
+	TxHash    string `json:"tx_hash"`   //部署交易
+	Block     int64  `json:"block"`     //部署高度，未知为-1
+	Timestamp int64  `json:"timestamp"` //部署时间，未知为-1
+	CodeHash  string `json:"code_hash"`
+	CodeSize  int64  `json:"code_size"`
+}
+
+func Main(cc echo.Context) error {
+
+	//Step 1. init x
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+	var (
+		input  Input
+		output Output
+	)
+	output.ErrNo = 0
+	output.ErrMsg = "success"
+
+	if err := c.BindInput(&input); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+
+	//get deployment from databases
+	contract, err := (&model.Contract{}).FindContractByAddr(c.Mysql(), input.Addr)
+	if err == nil {
+		output.Address = contract.F_address
+		output.Creator = contract.F_creator
+		output.TxHash = contract.F_tx_hash
+		output.Block = contract.F_block
+		output.Timestamp = contract.F_timestamp
+		output.CodeHash = contract.F_code_hash
+		output.CodeSize = contract.F_code_size
+		return c.RESULT(output)
+	}
+
+	//contracts in genesis (e.g. mortgage contract) have no deployment, only code on chain
+	webthree := web3.NewWeb3(providers.NewHTTPProvider(config.Config().Gate, config.Config().TimeOut.RPCTimeOut, false))
+	code, err := webthree.Eth.GetCode(input.Addr, block.LATEST)
+	if err != nil {
+		return c.RESULT_ERROR(ERR_RPC_ERROR, err.Error())
+	}
+
+	bytecode := common.FromHex(code)
+	if len(bytecode) == 0 {
+		log.Debugf("contract:%s not exist", input.Addr)
+		return c.RESULT_ERROR(CONTRACT_NOT_EXIST, "contract not exist")
+	}
+
+	output.Address = input.Addr
+	output.Block = -1
+	output.Timestamp = -1
+	output.CodeHash = crypto.Keccak256Hash(bytecode).Hex()
+	output.CodeSize = int64(len(bytecode))
+
+	return c.RESULT(output)
+}
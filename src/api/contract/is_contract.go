@@ -0,0 +1,28 @@
+package contract
+
+import (
+	"github.com/EthereumHD/EhdChain/common"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"go-web3"
+	"go-web3/eth/block"
+	"qoobing.com/utillib.golang/log"
+)
+
+// IsContract whether addr is a contract: deployed in an indexed block, or with code on chain like the
+// genesis contracts (e.g. mortgage contract) and the ones created by a contract, which t_contract does not have.
+// The code lookup is best effort, a gateway error is logged and counts as no code; only a database error is returned
+func IsContract(db *gorm.DB, webthree *web3.Web3, addr string) (bool, error) {
+	deployed, err := model.IsContract(db, addr)
+	if err != nil || deployed {
+		return deployed, err
+	}
+
+	code, err := webthree.Eth.GetCode(addr, block.LATEST)
+	if err != nil {
+		log.Debugf("Eth.GetCode error:%s,addr:%s", err.Error(), addr)
+		return false, nil
+	}
+
+	return len(common.FromHex(code)) > 0, nil
+}
@@ -0,0 +1,53 @@
+package label
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+// Delete_label remove the label of an address, admin only
+func Delete_label(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+	}
+
+	argc := new(InputAddrReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Delete_label: %+v", argc)
+
+	//检查权限和参数
+	if !isAdmin(c) {
+		log.Debugf("permission denied")
+		return c.RESULT_ERROR(ERR_PERMISSION_DENIED, "permission denied")
+	}
+	if argc.Addr == "" {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//写入数据库
+	if err := DeleteAddressLabel(c.Mysql(), argc.Addr); err != nil {
+		if err.Error() == DATA_NOT_EXIST {
+			return c.RESULT_ERROR(ERR_PARAMETER_INVALID, argc.Addr+" has no label")
+		}
+		log.Debugf("DeleteAddressLabel error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SAVE_ERROR, fmt.Sprintf("DeleteAddressLabel error:%s", err.Error()))
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,46 @@
+package label
+
+import (
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+func Get_label(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputLabelRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+	}
+
+	argc := new(InputAddrReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_label: %+v", argc)
+
+	//检查参数
+	if argc.Addr == "" {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	label, err := (&AddressLabel{}).FindAddressLabel(c.Mysql(), argc.Addr)
+	if err != nil {
+		log.Debugf("FindAddressLabel error:%s", err.Error())
+		return c.RESULT_ERROR(BLOCK_OR_TRANS_NOT_EXIST, argc.Addr+" has no label")
+	}
+
+	//返回结果
+	rsp.LabelInfo = toLabelInfo(label)
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,54 @@
+package label
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+func Get_labels(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputListRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Labels: []LabelInfo{},
+	}
+
+	argc := new(InputListReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_labels: %+v", argc)
+
+	//检查参数
+	if argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	labels, count, err := GetAddressLabelList(c.Mysql(), argc.Category, offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetAddressLabelList error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetAddressLabelList error:%s", err.Error()))
+	}
+
+	//包装参数
+	rsp.Count = count
+	for _, label := range labels {
+		rsp.Labels = append(rsp.Labels, toLabelInfo(label))
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,89 @@
+package label
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"github.com/labstack/echo"
+	"io"
+	"io/ioutil"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+// MAX_IMPORT_BYTES the largest body an import reads
+const MAX_IMPORT_BYTES = 4 << 20
+
+// Import_labels save the labels of a csv or json body in one transaction, admin only. The format is the
+// format query parameter, or json if the Content-Type is json and csv otherwise
+func Import_labels(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputImportRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+		if strings.Contains(c.Request().Header.Get(echo.HeaderContentType), "json") {
+			format = "json"
+		}
+	}
+	log.Debugf("receive Import_labels, format:%s", format)
+
+	//检查权限和参数
+	if !isAdmin(c) {
+		log.Debugf("permission denied")
+		return c.RESULT_ERROR(ERR_PERMISSION_DENIED, "permission denied")
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(c.Request().Body, MAX_IMPORT_BYTES+1))
+	if err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	if len(body) > MAX_IMPORT_BYTES {
+		return c.RESULT_PARAMETER_ERROR(fmt.Sprintf("body is larger than %d bytes", MAX_IMPORT_BYTES))
+	}
+	labels, err := parseLabels(format, body)
+	if err != nil {
+		log.Debugf("parseLabels error:%s", err.Error())
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	if len(labels) == 0 || len(labels) > MAX_IMPORT_LABELS {
+		return c.RESULT_PARAMETER_ERROR(fmt.Sprintf("import 1 to %d labels at a time", MAX_IMPORT_LABELS))
+	}
+	for i := range labels {
+		if err := checkLabel(&labels[i], "import"); err != nil {
+			return c.RESULT_PARAMETER_ERROR(fmt.Sprintf("label %d:%s", i+1, err.Error()))
+		}
+	}
+
+	//写入数据库
+	err = InTransaction(c.Mysql(), func(tx *gorm.DB) error {
+		for start := 0; start < len(labels); start += MAX_PAGE_SIZE {
+			end := start + MAX_PAGE_SIZE
+			if end > len(labels) {
+				end = len(labels)
+			}
+			if err := SaveAddressLabels(tx, labels[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debugf("SaveAddressLabels error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SAVE_ERROR, fmt.Sprintf("SaveAddressLabels error:%s", err.Error()))
+	}
+
+	//返回结果
+	rsp.Imported = len(labels)
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,152 @@
+package label
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/EthereumHD/EhdChain/common"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	"github.com/EthereumHD/Scan/src/config"
+	. "github.com/EthereumHD/Scan/src/model"
+	"io"
+	"strings"
+)
+
+const (
+	MAX_PAGE_SIZE      = 1000
+	MAX_IMPORT_LABELS  = 10000 //一次导入最多的标签数
+	MAX_LABEL_LENGTH   = 128
+	MAX_FIELD_LENGTH   = 64 //category和source
+	ADMIN_TOKEN_HEADER = "X-Admin-Token"
+)
+
+type InputSetReq struct {
+	Addr     string `json:"addr" form:"addr"`
+	Label    string `json:"label" form:"label"`
+	Category string `json:"category" form:"category"`
+	Source   string `json:"source" form:"source"` //默认admin
+}
+
+type InputAddrReq struct {
+	Addr string `json:"addr" form:"addr"`
+}
+
+type InputListReq struct {
+	Category  string `json:"category" form:"category"` //为空则查询所有分类
+	PageIndex int    `json:"pageIndex" form:"pageIndex"`
+	PageSize  int    `json:"pageSize" form:"pageSize"`
+}
+
+type LabelInfo struct {
+	Addr     string `json:"addr"`
+	Label    string `json:"label"`
+	Category string `json:"category"`
+	Source   string `json:"source"`
+}
+
+type OutputRsp struct {
+	ErrNo  int    `json:"err_no"`
+	ErrMsg string `json:"err_msg"`
+}
+
+type OutputLabelRsp struct {
+	ErrNo  int    `json:"err_no"`
+	ErrMsg string `json:"err_msg"`
+	LabelInfo
+}
+
+type OutputListRsp struct {
+	ErrNo  int         `json:"err_no"`
+	ErrMsg string      `json:"err_msg"`
+	Count  int64       `json:"count"` //标签个数
+	Labels []LabelInfo `json:"labels"`
+}
+
+type OutputImportRsp struct {
+	ErrNo    int    `json:"err_no"`
+	ErrMsg   string `json:"err_msg"`
+	Imported int    `json:"imported"`
+}
+
+// isAdmin whether the request carries the configured admin token, always false if none is configured
+func isAdmin(c ApiContext) bool {
+	token := config.Config().Admin.Token
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(c.Request().Header.Get(ADMIN_TOKEN_HEADER)), []byte(token)) == 1
+}
+
+// checkLabel normalize a label to be saved, source defaults to def
+func checkLabel(label *AddressLabel, def string) error {
+	label.F_addr = strings.ToLower(strings.TrimSpace(label.F_addr))
+	label.F_label = strings.TrimSpace(label.F_label)
+	label.F_category = strings.TrimSpace(label.F_category)
+	label.F_source = strings.TrimSpace(label.F_source)
+	if label.F_source == "" {
+		label.F_source = def
+	}
+
+	if !strings.HasPrefix(label.F_addr, "0x") || !common.IsHexAddress(label.F_addr) {
+		return errors.New(label.F_addr + " is not an address")
+	}
+	if label.F_label == "" || len(label.F_label) > MAX_LABEL_LENGTH {
+		return errors.New("label of " + label.F_addr + " should be 1 to 128 bytes")
+	}
+	if len(label.F_category) > MAX_FIELD_LENGTH || len(label.F_source) > MAX_FIELD_LENGTH {
+		return errors.New("category or source of " + label.F_addr + " is longer than 64 bytes")
+	}
+	return nil
+}
+
+// parseLabels decode an import, csv rows of addr,label[,category[,source]] with an optional header row,
+// or a json array of LabelInfo
+func parseLabels(format string, body []byte) (labels []AddressLabel, err error) {
+	switch format {
+	case "csv":
+		reader := csv.NewReader(bytes.NewReader(body))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		for line := 1; ; line++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "addr") {
+				continue
+			}
+			if len(record) < 2 || len(record) > 4 {
+				return nil, fmt.Errorf("line %d should be addr,label[,category[,source]]", line)
+			}
+			record = append(record, "", "")
+			labels = append(labels, AddressLabel{F_addr: record[0], F_label: record[1], F_category: record[2], F_source: record[3]})
+		}
+	case "json":
+		infos := make([]LabelInfo, 0)
+		if err := json.Unmarshal(body, &infos); err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			labels = append(labels, AddressLabel{F_addr: info.Addr, F_label: info.Label, F_category: info.Category, F_source: info.Source})
+		}
+	default:
+		return nil, errors.New("format should be csv or json")
+	}
+
+	return labels, nil
+}
+
+func toLabelInfo(label AddressLabel) LabelInfo {
+	return LabelInfo{
+		Addr:     label.F_addr,
+		Label:    label.F_label,
+		Category: label.F_category,
+		Source:   label.F_source,
+	}
+}
@@ -0,0 +1,53 @@
+package label
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/EthereumHD/Scan/src/model"
+)
+
+func TestParseLabels(t *testing.T) {
+	addr := "0x00000000000000000000000000000000000000ab"
+
+	tests := []struct {
+		Format   string
+		Body     string
+		Expected []AddressLabel
+		Error    bool
+	}{
+		{"csv", addr + ",Pool A", []AddressLabel{{F_addr: addr, F_label: "Pool A"}}, false},
+		{"csv", addr + ",Pool A,pool", []AddressLabel{{F_addr: addr, F_label: "Pool A", F_category: "pool"}}, false},
+		{"csv", addr + ", Pool A, pool, site", []AddressLabel{{F_addr: addr, F_label: "Pool A", F_category: "pool", F_source: "site"}}, false},
+		{"csv", "addr,label,category,source\n" + addr + ",Pool A\n" + addr + ",Pool B,pool",
+			[]AddressLabel{{F_addr: addr, F_label: "Pool A"}, {F_addr: addr, F_label: "Pool B", F_category: "pool"}}, false},
+		{"csv", " ADDR ,label\n" + addr + ",Pool A", []AddressLabel{{F_addr: addr, F_label: "Pool A"}}, false},
+		{"csv", addr + ",Pool A\naddr,label", []AddressLabel{{F_addr: addr, F_label: "Pool A"}, {F_addr: "addr", F_label: "label"}}, false},
+		{"csv", addr, nil, true},
+		{"csv", addr + ",Pool A,pool,site,extra", nil, true},
+		{"csv", addr + ",\"Pool A", nil, true},
+		{"csv", "", nil, false},
+		{"json", `[{"addr":"` + addr + `","label":"Pool A","category":"pool","source":"site"}]`,
+			[]AddressLabel{{F_addr: addr, F_label: "Pool A", F_category: "pool", F_source: "site"}}, false},
+		{"json", `[]`, nil, false},
+		{"json", `{"addr":"` + addr + `"}`, nil, true},
+		{"xml", addr + ",Pool A", nil, true},
+	}
+
+	for _, tc := range tests {
+		labels, err := parseLabels(tc.Format, []byte(tc.Body))
+		if tc.Error {
+			if err == nil {
+				t.Errorf("%s %q: parsed %+v; want an error", tc.Format, tc.Body, labels)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %q: failed to parse: err=%q", tc.Format, tc.Body, err)
+			continue
+		}
+		if !reflect.DeepEqual(labels, tc.Expected) {
+			t.Errorf("%s %q: got %+v; want %+v", tc.Format, tc.Body, labels, tc.Expected)
+		}
+	}
+}
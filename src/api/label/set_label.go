@@ -0,0 +1,52 @@
+package label
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+// Set_label label an address or change its label, admin only
+func Set_label(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputLabelRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+	}
+
+	argc := new(InputSetReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Set_label: %+v", argc)
+
+	//检查权限和参数
+	if !isAdmin(c) {
+		log.Debugf("permission denied")
+		return c.RESULT_ERROR(ERR_PERMISSION_DENIED, "permission denied")
+	}
+	label := AddressLabel{F_addr: argc.Addr, F_label: argc.Label, F_category: argc.Category, F_source: argc.Source}
+	if err := checkLabel(&label, "admin"); err != nil {
+		log.Debugf("param error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, err.Error())
+	}
+
+	//写入数据库
+	if err := SaveAddressLabels(c.Mysql(), []AddressLabel{label}); err != nil {
+		log.Debugf("SaveAddressLabels error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SAVE_ERROR, fmt.Sprintf("SaveAddressLabels error:%s", err.Error()))
+	}
+
+	//返回结果
+	rsp.LabelInfo = toLabelInfo(label)
+	return c.RESULT(rsp)
+}
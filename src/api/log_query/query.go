@@ -0,0 +1,108 @@
+package log_query
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+const MAX_PAGE_SIZE = 1000
+
+type QueryReq struct {
+	Address   string `json:"address" form:"address"` //合约地址
+	Topic0    string `json:"topic0" form:"topic0"`   //事件签名
+	Topic1    string `json:"topic1" form:"topic1"`
+	Topic2    string `json:"topic2" form:"topic2"`
+	Topic3    string `json:"topic3" form:"topic3"`
+	FromBlock int64  `json:"from_block" form:"from_block"` //起始高度
+	ToBlock   int64  `json:"to_block" form:"to_block"`     //结束高度，0为最新
+	PageIndex int    `json:"pageIndex" form:"pageIndex"`
+	PageSize  int    `json:"pageSize" form:"pageSize"`
+}
+
+type QueryRsp struct {
+	ErrNo  int       `json:"err_no"`
+	ErrMsg string    `json:"err_msg"`
+	Count  int64     `json:"count"` //符合条件的日志个数
+	Logs   []LogInfo `json:"logs"`
+}
+
+type LogInfo struct {
+	Address     string   `json:"address"`
+	Topics      []string `json:"topics"`
+	Data        string   `json:"data"`
+	BlockNumber int64    `json:"block_number"`
+	BlockHash   string   `json:"block_hash"`
+	Timestamp   int64    `json:"timestamp"`
+	TxHash      string   `json:"tx_hash"`
+	TxIndex     int64    `json:"tx_index"`
+	LogIndex    int64    `json:"log_index"`
+}
+
+func Query(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := QueryRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Logs:   []LogInfo{},
+	}
+
+	argc := new(QueryReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Query: %+v", argc)
+
+	//检查参数
+	if argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	if argc.FromBlock < 0 || argc.ToBlock < 0 || (argc.ToBlock > 0 && argc.ToBlock < argc.FromBlock) {
+		log.Debugf("block range error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "block range error")
+	}
+
+	filter := LogFilter{
+		Address:   strings.ToLower(argc.Address),
+		Topics:    [4]string{strings.ToLower(argc.Topic0), strings.ToLower(argc.Topic1), strings.ToLower(argc.Topic2), strings.ToLower(argc.Topic3)},
+		FromBlock: argc.FromBlock,
+		ToBlock:   argc.ToBlock,
+	}
+
+	//查询日志,数据库查询
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	logs, count, err := QueryLogs(c.Mysql(), filter, offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("QueryLogs error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("QueryLogs error:%s", err.Error()))
+	}
+	rsp.Count = count
+
+	//包装参数
+	for _, l := range logs {
+		rsp.Logs = append(rsp.Logs, LogInfo{
+			Address:     l.F_address,
+			Topics:      l.Topics(),
+			Data:        l.F_data,
+			BlockNumber: l.F_block,
+			BlockHash:   l.F_block_hash,
+			Timestamp:   l.F_timestamp,
+			TxHash:      l.F_tx_hash,
+			TxIndex:     l.F_tx_index,
+			LogIndex:    l.F_log_index,
+		})
+	}
+	//返回结果
+	return c.RESULT(rsp)
+}
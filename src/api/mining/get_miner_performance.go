@@ -0,0 +1,134 @@
+package mining
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/metrics"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+const MAX_PERFORMANCE_DAYS = 100
+
+type InputPerformanceReq struct {
+	Addr       string  `json:"addr" form:"addr"`
+	StartDate  string  `json:"start_date" form:"start_date"`   //2006-01-02，UTC，默认30天前
+	EndDate    string  `json:"end_date" form:"end_date"`       //2006-01-02，UTC，包含当天，默认今天
+	DeclaredTB float64 `json:"declared_tb" form:"declared_tb"` //矿工声明的容量(TB)，可不填
+}
+
+type PerformanceInfo struct {
+	Date              string  `json:"date,omitempty"`
+	Blocks            int64   `json:"blocks"`             //全网爆块数
+	Won               int64   `json:"won"`                //矿工爆块数
+	Share             float64 `json:"share"`              //won/blocks
+	NetworkCapacity   int64   `json:"network_capacity"`   //由平均难度估算(byte)
+	EffectiveCapacity int64   `json:"effective_capacity"` //share*network_capacity(byte)
+	Expected          float64 `json:"expected"`           //按声明容量应爆块数，没有声明容量为0
+	Luck              float64 `json:"luck"`               //won/expected*100
+}
+
+type OutputPerformanceRsp struct {
+	ErrNo   int               `json:"err_no"`
+	ErrMsg  string            `json:"err_msg"`
+	Overall PerformanceInfo   `json:"overall"`
+	Days    []PerformanceInfo `json:"days"`
+}
+
+// Get_miner_performance estimate the capacity of a miner from its win rate against the network capacity
+func Get_miner_performance(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputPerformanceRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Days:   []PerformanceInfo{},
+	}
+
+	argc := new(InputPerformanceReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_miner_performance: %+v", argc)
+
+	//检查参数，不能超过100天
+	withDate := "2006-01-02"
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	start, end := today.AddDate(0, 0, -29), today
+	var err error
+	if argc.StartDate != "" {
+		if start, err = time.ParseInLocation(withDate, argc.StartDate, time.UTC); err != nil {
+			return c.RESULT_PARAMETER_ERROR(argc.StartDate + " date error")
+		}
+	}
+	if argc.EndDate != "" {
+		if end, err = time.ParseInLocation(withDate, argc.EndDate, time.UTC); err != nil {
+			return c.RESULT_PARAMETER_ERROR(argc.EndDate + " date error")
+		}
+	}
+	end = end.AddDate(0, 0, 1)
+	if argc.Addr == "" || argc.DeclaredTB < 0 || !start.Before(end) || end.Sub(start) > MAX_PERFORMANCE_DAYS*24*time.Hour {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	declared := argc.DeclaredTB * metrics.TB
+
+	//查询数据库
+	days, err := GetMinerDailyBlocks(c.Mysql(), strings.ToLower(argc.Addr), start.Unix(), end.Unix())
+	if err != nil {
+		log.Debugf("GetMinerDailyBlocks error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetMinerDailyBlocks error:%s", err.Error()))
+	}
+
+	//包装参数
+	var wonCapacity, blocksCapacity float64
+	for _, day := range days {
+		capacity := metrics.CapacityOf(day.Difficulty)
+		info := PerformanceInfo{
+			Date:            time.Unix(day.Day, 0).UTC().Format(withDate),
+			Blocks:          day.Blocks,
+			Won:             day.Won,
+			NetworkCapacity: int64(capacity),
+		}
+		if day.Blocks > 0 {
+			info.Share = float64(day.Won) / float64(day.Blocks)
+			info.EffectiveCapacity = int64(info.Share * capacity)
+		}
+		if declared > 0 && capacity > 0 {
+			info.Expected = float64(day.Blocks) * declared / capacity
+			info.Luck = luck(day.Won, info.Expected)
+		}
+		rsp.Days = append(rsp.Days, info)
+
+		rsp.Overall.Blocks += day.Blocks
+		rsp.Overall.Won += day.Won
+		rsp.Overall.Expected += info.Expected
+		wonCapacity += float64(day.Won) * capacity
+		blocksCapacity += float64(day.Blocks) * capacity
+	}
+	if rsp.Overall.Blocks > 0 {
+		rsp.Overall.Share = float64(rsp.Overall.Won) / float64(rsp.Overall.Blocks)
+		rsp.Overall.NetworkCapacity = int64(blocksCapacity / float64(rsp.Overall.Blocks))
+		rsp.Overall.EffectiveCapacity = int64(wonCapacity / float64(rsp.Overall.Blocks))
+	}
+	rsp.Overall.Luck = luck(rsp.Overall.Won, rsp.Overall.Expected)
+
+	//返回结果
+	return c.RESULT(rsp)
+}
+
+func luck(won int64, expected float64) float64 {
+	if expected <= 0 {
+		return 0
+	}
+	return float64(won) / expected * 100
+}
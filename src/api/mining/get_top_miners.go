@@ -0,0 +1,134 @@
+package mining
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/metrics"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"time"
+)
+
+const MAX_TOP_MINERS_PAGE_SIZE = 1000
+
+// topMinersWindows the hours of each window, 0 for all-time
+var topMinersWindows = map[string]int64{
+	"24h": 24,
+	"7d":  7 * 24,
+	"30d": 30 * 24,
+	"all": 0,
+}
+
+type InputTopMinersReq struct {
+	Window    string `json:"window" form:"window"`     //24h，7d，30d，all，默认24h
+	OrderBy   string `json:"order_by" form:"order_by"` //blocks，reward，fees，默认blocks
+	PageIndex int    `json:"pageIndex" form:"pageIndex"`
+	PageSize  int    `json:"pageSize" form:"pageSize"`
+}
+
+type TopMinerInfo struct {
+	Rank              int     `json:"rank"`
+	Miner             string  `json:"miner"`
+	Blocks            int64   `json:"blocks"`
+	Reward            string  `json:"reward"`
+	Fees              string  `json:"fees"`
+	Share             float64 `json:"share"`              //blocks/全网blocks
+	EstimatedCapacity int64   `json:"estimated_capacity"` //share*network_capacity(byte)
+}
+
+type OutputTopMinersRsp struct {
+	ErrNo           int            `json:"err_no"`
+	ErrMsg          string         `json:"err_msg"`
+	Window          string         `json:"window"`
+	Start           int64          `json:"start"`            //窗口的起点，按整点对齐
+	Blocks          int64          `json:"blocks"`           //窗口内全网爆块数
+	NetworkCapacity int64          `json:"network_capacity"` //由窗口内平均难度估算(byte)
+	Count           int64          `json:"count"`            //矿工个数
+	Miners          []TopMinerInfo `json:"miners"`
+}
+
+// Get_top_miners rank the miners of a window by blocks, reward or fees
+func Get_top_miners(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputTopMinersRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Miners: []TopMinerInfo{},
+	}
+
+	argc := new(InputTopMinersReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_top_miners: %+v", argc)
+
+	//检查参数
+	if argc.Window == "" {
+		argc.Window = "24h"
+	}
+	if argc.OrderBy == "" {
+		argc.OrderBy = "blocks"
+	}
+	if argc.PageIndex == 0 {
+		argc.PageIndex = 1
+	}
+	if argc.PageSize == 0 {
+		argc.PageSize = 20
+	}
+	hours, ok := topMinersWindows[argc.Window]
+	_, known := MinerRankOrders[argc.OrderBy]
+	if !ok || !known || argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_TOP_MINERS_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	rsp.Window = argc.Window
+	if hours > 0 {
+		rsp.Start = HourOf(time.Now().Unix()) - (hours-1)*HOURSECONDS
+	}
+
+	//查询数据库
+	total, err := GetMinerWindowTotal(c.Mysql(), rsp.Start)
+	if err != nil {
+		log.Debugf("GetMinerWindowTotal error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetMinerWindowTotal error:%s", err.Error()))
+	}
+
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	ranks, count, err := GetTopMiners(c.Mysql(), rsp.Start, argc.OrderBy, offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetTopMiners error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTopMiners error:%s", err.Error()))
+	}
+
+	//包装参数
+	rsp.Blocks = total.Blocks
+	rsp.Count = count
+	if total.DifficultyBlocks > 0 {
+		rsp.NetworkCapacity = int64(metrics.CapacityOf(total.DifficultySum / float64(total.DifficultyBlocks)))
+	}
+	for i, rank := range ranks {
+		info := TopMinerInfo{
+			Rank:   offset + i + 1,
+			Miner:  rank.F_miner,
+			Blocks: rank.Blocks,
+			Reward: rank.Reward,
+			Fees:   rank.Fees,
+		}
+		if total.Blocks > 0 {
+			info.Share = float64(rank.Blocks) / float64(total.Blocks)
+			info.EstimatedCapacity = int64(info.Share * float64(rsp.NetworkCapacity))
+		}
+		rsp.Miners = append(rsp.Miners, info)
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
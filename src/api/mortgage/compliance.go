@@ -0,0 +1,105 @@
+package mortgage
+
+import (
+	"errors"
+	"github.com/EthereumHD/Scan/src/config"
+	"github.com/EthereumHD/Scan/src/metrics"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"math/big"
+)
+
+const MAX_COMPLIANCE_DAYS = 90
+
+type InputComplianceReq struct {
+	Addr string `json:"addr" form:"addr"`
+	Days int64  `json:"days" form:"days"` //按最近几天的爆块估算容量，默认7
+}
+
+type InputUnderReq struct {
+	Days      int64 `json:"days" form:"days"`
+	PageIndex int   `json:"pageIndex" form:"pageIndex"`
+	PageSize  int   `json:"pageSize" form:"pageSize"`
+}
+
+type ComplianceInfo struct {
+	Miner             string  `json:"miner,omitempty"`
+	Date              string  `json:"date,omitempty"`
+	Won               int64   `json:"won"`                //爆块数
+	EffectiveCapacity int64   `json:"effective_capacity"` //由爆块率估算的容量(byte)
+	Stake             string  `json:"stake"`              //当前抵押(wei)
+	Required          string  `json:"required"`           //容量需要的抵押(wei)
+	Coverage          float64 `json:"coverage"`           //stake/required，required为0时为0
+	Shortfall         string  `json:"shortfall"`          //required-stake，足额为0
+	Compliant         bool    `json:"compliant"`          //抵押覆盖容量
+}
+
+type UnderInfo struct {
+	ComplianceInfo
+	PrevCoverage float64 `json:"prev_coverage"` //上一个相同长度窗口的coverage
+	Trend        float64 `json:"trend"`         //coverage-prev_coverage，负数为恶化
+}
+
+type OutputComplianceRsp struct {
+	ErrNo  int    `json:"err_no"`
+	ErrMsg string `json:"err_msg"`
+	ComplianceInfo
+	Trend []ComplianceInfo `json:"trend"` //每天
+}
+
+type OutputUnderRsp struct {
+	ErrNo  int         `json:"err_no"`
+	ErrMsg string      `json:"err_msg"`
+	Count  int64       `json:"count"` //抵押不足的矿工个数
+	Miners []UnderInfo `json:"miners"`
+}
+
+// mortgagePerTB the stake required per TB of capacity, from the config
+func mortgagePerTB() (*big.Int, error) {
+	perTB, ok := big.NewInt(0).SetString(config.Config().Metrics.MortgagePerTB, 10)
+	if !ok || perTB.Sign() <= 0 {
+		return nil, errors.New("MortgagePerTB is not configured")
+	}
+	return perTB, nil
+}
+
+// networkCapacity the blocks of [start, end) and the network capacity averaged over them
+func networkCapacity(db *gorm.DB, start int64, end int64) (blocks int64, capacity float64, err error) {
+	days, err := GetMinerDailyBlocks(db, "", start, end)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var weighted float64
+	for _, day := range days {
+		blocks += day.Blocks
+		weighted += float64(day.Blocks) * metrics.CapacityOf(day.Difficulty)
+	}
+	if blocks > 0 {
+		capacity = weighted / float64(blocks)
+	}
+	return blocks, capacity, nil
+}
+
+// newComplianceInfo compare stake with the stake required by capacity bytes
+func newComplianceInfo(won int64, capacity float64, stake string, perTB *big.Int) ComplianceInfo {
+	info := ComplianceInfo{Won: won, EffectiveCapacity: int64(capacity), Stake: stake}
+
+	staked, ok := big.NewInt(0).SetString(stake, 10)
+	if !ok {
+		staked = big.NewInt(0)
+	}
+	required, _ := new(big.Float).Mul(big.NewFloat(capacity/metrics.TB), new(big.Float).SetInt(perTB)).Int(nil)
+	shortfall := big.NewInt(0).Sub(required, staked)
+	if shortfall.Sign() < 0 {
+		shortfall.SetInt64(0)
+	}
+
+	info.Required = required.String()
+	info.Shortfall = shortfall.String()
+	info.Compliant = shortfall.Sign() == 0
+	if required.Sign() > 0 {
+		info.Coverage, _ = new(big.Float).Quo(new(big.Float).SetInt(staked), new(big.Float).SetInt(required)).Float64()
+	}
+	return info
+}
@@ -0,0 +1,102 @@
+package mortgage
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/metrics"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// Get_compliance whether the stake of a miner covers the capacity it wins blocks with, and each day of the window
+func Get_compliance(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputComplianceRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Trend:  []ComplianceInfo{},
+	}
+
+	argc := new(InputComplianceReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_compliance: %+v", argc)
+
+	//检查参数
+	if argc.Days == 0 {
+		argc.Days = 7
+	}
+	if argc.Addr == "" || argc.Days < 0 || argc.Days > MAX_COMPLIANCE_DAYS {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	perTB, err := mortgagePerTB()
+	if err != nil {
+		return c.RESULT_ERROR(ERR_INNER_ERROR, err.Error())
+	}
+	addr := strings.ToLower(argc.Addr)
+	end := time.Now().Unix()
+	start := end - argc.Days*86400
+
+	//查询数据库
+	days, err := GetMinerDailyBlocks(c.Mysql(), addr, start, end)
+	if err != nil {
+		log.Debugf("GetMinerDailyBlocks error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetMinerDailyBlocks error:%s", err.Error()))
+	}
+
+	stake, err := GetMortgageStake(c.Mysql(), addr, -1)
+	if err != nil {
+		log.Debugf("GetMortgageStake error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetMortgageStake error:%s", err.Error()))
+	}
+
+	//包装参数，每天的容量用当天的爆块率，抵押用当天结束时的
+	var won, blocks int64
+	var wonCapacity float64
+	for _, day := range days {
+		capacity := metrics.CapacityOf(day.Difficulty)
+		won += day.Won
+		blocks += day.Blocks
+		wonCapacity += float64(day.Won) * capacity
+
+		dayEnd := day.Day + 86400
+		if dayEnd > end {
+			dayEnd = end
+		}
+		stakes, err := GetMortgageStakesBefore(c.Mysql(), []string{addr}, dayEnd)
+		if err != nil {
+			log.Debugf("GetMortgageStakesBefore error:%s", err.Error())
+			return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetMortgageStakesBefore error:%s", err.Error()))
+		}
+		dayStake := "0"
+		if len(stakes) > 0 {
+			dayStake = stakes[0].Stake
+		}
+
+		info := newComplianceInfo(day.Won, float64(day.Won)/float64(day.Blocks)*capacity, dayStake, perTB)
+		info.Date = time.Unix(day.Day, 0).UTC().Format("2006-01-02")
+		rsp.Trend = append(rsp.Trend, info)
+	}
+
+	var effective float64
+	if blocks > 0 {
+		effective = wonCapacity / float64(blocks)
+	}
+	rsp.ComplianceInfo = newComplianceInfo(won, effective, stake.Stake, perTB)
+	rsp.Miner = addr
+
+	//返回结果
+	return c.RESULT(rsp)
+}
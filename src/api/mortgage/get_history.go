@@ -0,0 +1,91 @@
+package mortgage
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+func Get_history(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputHistoryRsp{
+		ErrNo:   0,
+		ErrMsg:  "success",
+		History: []HistoryInfo{},
+	}
+
+	argc := new(InputHistoryReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_history: %+v", argc)
+
+	//检查参数
+	if argc.Addr == "" || argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	addr := strings.ToLower(argc.Addr)
+
+	//查询数据库
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	mortgages, count, err := GetMortgageHistory(c.Mysql(), addr, offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetMortgageHistory error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetMortgageHistory error:%s", err.Error()))
+	}
+	rsp.Count = count
+	if len(mortgages) == 0 {
+		return c.RESULT(rsp)
+	}
+
+	//stake after the newest call of the page, the older ones go back from it
+	newest := mortgages[0]
+	stake, err := GetMortgageStakeAt(c.Mysql(), addr, newest.F_block, newest.F_tx_index)
+	if err != nil {
+		log.Debugf("GetMortgageStakeAt error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetMortgageStakeAt error:%s", err.Error()))
+	}
+	stakeAfter, b := big.NewInt(0).SetString(stake.Stake, 10)
+	if b == false {
+		return c.RESULT_ERROR(ERR_INNER_ERROR, "invalid stake:"+stake.Stake)
+	}
+
+	//包装参数
+	for _, m := range mortgages {
+		rsp.History = append(rsp.History, HistoryInfo{
+			TxHash:      m.F_tx_hash,
+			BlockNumber: m.F_block,
+			Timestamp:   m.F_timestamp,
+			From:        m.F_from,
+			TxType:      m.F_type,
+			Amount:      m.F_amount,
+			Success:     m.F_success == 1,
+			StakeAfter:  stakeAfter.String(),
+		})
+
+		amount, b := big.NewInt(0).SetString(m.F_amount, 10)
+		if b == false || m.F_success != 1 {
+			continue
+		}
+		if m.F_type == TX_TYPE_ME_MORTGAGE {
+			stakeAfter.Sub(stakeAfter, amount)
+		} else {
+			stakeAfter.Add(stakeAfter, amount)
+		}
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,54 @@
+package mortgage
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+func Get_stake(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputStakeRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+	}
+
+	argc := new(InputAddrReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_stake: %+v", argc)
+
+	//检查参数
+	if argc.Addr == "" {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	stake, err := GetMortgageStake(c.Mysql(), strings.ToLower(argc.Addr), -1)
+	if err != nil {
+		log.Debugf("GetMortgageStake error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetMortgageStake error:%s", err.Error()))
+	}
+
+	rsp.StakeInfo = StakeInfo{
+		Addr:      stake.F_addr,
+		Stake:     stake.Stake,
+		Mortgaged: stake.Mortgaged,
+		Redeemed:  stake.Redeemed,
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
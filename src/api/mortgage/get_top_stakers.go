@@ -0,0 +1,59 @@
+package mortgage
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+func Get_top_stakers(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputTopStakersRsp{
+		ErrNo:   0,
+		ErrMsg:  "success",
+		Stakers: []StakeInfo{},
+	}
+
+	argc := new(InputReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_top_stakers: %+v", argc)
+
+	//检查参数
+	if argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	stakes, count, err := GetTopStakers(c.Mysql(), offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetTopStakers error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTopStakers error:%s", err.Error()))
+	}
+	rsp.Count = count
+
+	//包装参数
+	for _, s := range stakes {
+		rsp.Stakers = append(rsp.Stakers, StakeInfo{
+			Addr:      s.F_addr,
+			Stake:     s.Stake,
+			Mortgaged: s.Mortgaged,
+			Redeemed:  s.Redeemed,
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,66 @@
+package mortgage
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	"github.com/EthereumHD/Scan/src/config"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"go-web3"
+	"go-web3/providers"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+)
+
+// Get_total reconcile the ledger with eth_getTotalMortgage at the last synced height
+func Get_total(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputTotalRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+	}
+
+	state, err := (&SyncState{}).FindSyncState(c.Mysql(), SYNC_STATE_LIVE)
+	if err != nil {
+		log.Debugf("FindSyncState error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, err.Error())
+	}
+	rsp.Height = state.F_height
+
+	//查询数据库
+	stake, err := GetTotalStake(c.Mysql(), state.F_height)
+	if err != nil {
+		log.Debugf("GetTotalStake error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTotalStake error:%s", err.Error()))
+	}
+	total, b := big.NewInt(0).SetString(stake.Stake, 10)
+	if b == false {
+		return c.RESULT_ERROR(ERR_INNER_ERROR, "invalid stake:"+stake.Stake)
+	}
+
+	//查询链上
+	webthree := web3.NewWeb3(providers.NewHTTPProvider(config.Config().Gate, config.Config().TimeOut.RPCTimeOut, false))
+	chainTotal, err := webthree.Eth.GetTotalMortgage(fmt.Sprintf("0x%x", state.F_height))
+	if err != nil {
+		log.Debugf("GetTotalMortgage:%d error:%s", state.F_height, err.Error())
+		return c.RESULT_ERROR(ERR_RPC_ERROR, err.Error())
+	}
+
+	drift := big.NewInt(0).Sub(chainTotal, total)
+	rsp.Total = total.String()
+	rsp.ChainTotal = chainTotal.String()
+	rsp.Drift = drift.String()
+	rsp.Drifted = drift.Sign() != 0
+	if rsp.Drifted {
+		log.Noticef("mortgage ledger drift at height:%d,ledger:%s,chain:%s", state.F_height, rsp.Total, rsp.ChainTotal)
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
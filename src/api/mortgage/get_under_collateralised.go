@@ -0,0 +1,135 @@
+package mortgage
+
+import (
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"github.com/labstack/echo"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+	"sort"
+	"time"
+)
+
+// Get_under_collateralised the miners whose stake does not cover the capacity they won blocks with in
+// the window, largest shortfall first, with their coverage in the window before
+func Get_under_collateralised(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputUnderRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Miners: []UnderInfo{},
+	}
+
+	argc := new(InputUnderReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_under_collateralised: %+v", argc)
+
+	//检查参数
+	if argc.Days == 0 {
+		argc.Days = 7
+	}
+	if argc.Days < 0 || argc.Days > MAX_COMPLIANCE_DAYS || argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	perTB, err := mortgagePerTB()
+	if err != nil {
+		return c.RESULT_ERROR(ERR_INNER_ERROR, err.Error())
+	}
+	end := time.Now().Unix()
+	start := end - argc.Days*86400
+
+	//查询数据库
+	current, err := windowCompliance(c.Mysql(), start, end, perTB)
+	if err != nil {
+		log.Debugf("windowCompliance error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, err.Error())
+	}
+	previous, err := windowCompliance(c.Mysql(), start-argc.Days*86400, start, perTB)
+	if err != nil {
+		log.Debugf("windowCompliance error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, err.Error())
+	}
+
+	//包装参数
+	under := make([]UnderInfo, 0)
+	shortfalls := make(map[string]*big.Int)
+	for miner, info := range current {
+		if info.Compliant {
+			continue
+		}
+		u := UnderInfo{ComplianceInfo: info, PrevCoverage: previous[miner].Coverage}
+		u.Trend = u.Coverage - u.PrevCoverage
+		under = append(under, u)
+		shortfalls[miner], _ = big.NewInt(0).SetString(info.Shortfall, 10)
+	}
+	sort.Slice(under, func(i, j int) bool {
+		if cmp := shortfalls[under[i].Miner].Cmp(shortfalls[under[j].Miner]); cmp != 0 {
+			return cmp > 0
+		}
+		return under[i].Miner < under[j].Miner
+	})
+
+	rsp.Count = int64(len(under))
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	if offset < len(under) {
+		last := offset + argc.PageSize
+		if last > len(under) {
+			last = len(under)
+		}
+		rsp.Miners = under[offset:last]
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
+
+// windowCompliance the compliance of each miner that won a block in [start, end), with its stake at end
+func windowCompliance(db *gorm.DB, start int64, end int64, perTB *big.Int) (map[string]ComplianceInfo, error) {
+	blocks, capacity, err := networkCapacity(db, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	wins, err := GetMinerWins(db, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	miners := make([]string, 0, len(wins))
+	for _, win := range wins {
+		miners = append(miners, win.F_miner)
+	}
+	stakes, err := GetMortgageStakesBefore(db, miners, end)
+	if err != nil {
+		return nil, err
+	}
+	staked := make(map[string]string)
+	for _, stake := range stakes {
+		staked[stake.F_addr] = stake.Stake
+	}
+
+	infos := make(map[string]ComplianceInfo)
+	for _, win := range wins {
+		stake, ok := staked[win.F_miner]
+		if !ok {
+			stake = "0"
+		}
+
+		//blocks is not 0 with a win in the same range
+		info := newComplianceInfo(win.Won, float64(win.Won)/float64(blocks)*capacity, stake, perTB)
+		info.Miner = win.F_miner
+		infos[win.F_miner] = info
+	}
+	return infos, nil
+}
@@ -0,0 +1,66 @@
+package mortgage
+
+const MAX_PAGE_SIZE = 1000
+
+type InputAddrReq struct {
+	Addr string `json:"addr" form:"addr"`
+}
+
+type InputHistoryReq struct {
+	Addr      string `json:"addr" form:"addr"`
+	PageIndex int    `json:"pageIndex" form:"pageIndex"`
+	PageSize  int    `json:"pageSize" form:"pageSize"`
+}
+
+type InputReq struct {
+	PageIndex int `json:"pageIndex" form:"pageIndex"`
+	PageSize  int `json:"pageSize" form:"pageSize"`
+}
+
+type StakeInfo struct {
+	Addr      string `json:"addr"`
+	Stake     string `json:"stake"`     //当前抵押(wei)
+	Mortgaged string `json:"mortgaged"` //累计抵押(wei)
+	Redeemed  string `json:"redeemed"`  //累计赎回(wei)
+}
+
+type HistoryInfo struct {
+	TxHash      string `json:"tx_hash"`
+	BlockNumber int64  `json:"block_number"`
+	Timestamp   int64  `json:"timestamp"`
+	From        string `json:"from"`
+	TxType      int64  `json:"tx_type"` //3 抵押，4 赎回
+	Amount      string `json:"amount"`
+	Success     bool   `json:"success"`     //失败的调用不计入抵押
+	StakeAfter  string `json:"stake_after"` //这次调用后的抵押
+}
+
+type OutputStakeRsp struct {
+	ErrNo  int    `json:"err_no"`
+	ErrMsg string `json:"err_msg"`
+	StakeInfo
+}
+
+type OutputHistoryRsp struct {
+	ErrNo   int           `json:"err_no"`
+	ErrMsg  string        `json:"err_msg"`
+	Count   int64         `json:"count"` //调用个数
+	History []HistoryInfo `json:"history"`
+}
+
+type OutputTopStakersRsp struct {
+	ErrNo   int         `json:"err_no"`
+	ErrMsg  string      `json:"err_msg"`
+	Count   int64       `json:"count"` //有抵押的地址个数
+	Stakers []StakeInfo `json:"stakers"`
+}
+
+type OutputTotalRsp struct {
+	ErrNo      int    `json:"err_no"`
+	ErrMsg     string `json:"err_msg"`
+	Height     int64  `json:"height"`      //对账的高度，最后同步的区块
+	Total      string `json:"total"`       //数据库中的总抵押
+	ChainTotal string `json:"chain_total"` //eth_getTotalMortgage
+	Drift      string `json:"drift"`       //chain_total - total
+	Drifted    bool   `json:"drifted"`     //不一致，可能缺少历史区块或有合约内部调用
+}
@@ -0,0 +1,61 @@
+package nft
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+func Get_by_owner(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputOwnerRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Nfts:   []NftInfo{},
+	}
+
+	argc := new(InputOwnerReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_by_owner: %+v", argc)
+
+	//检查参数
+	if argc.Owner == "" || argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	owners, count, err := GetNftsByOwner(c.Mysql(), strings.ToLower(argc.Owner), strings.ToLower(argc.Token), offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetNftsByOwner error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetNftsByOwner error:%s", err.Error()))
+	}
+	rsp.Count = count
+
+	//包装参数
+	for _, o := range owners {
+		rsp.Nfts = append(rsp.Nfts, NftInfo{
+			Token:       o.F_token,
+			TokenId:     o.F_token_id,
+			Owner:       o.F_owner,
+			TokenURI:    o.F_token_uri,
+			BlockNumber: o.F_block,
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,68 @@
+package nft
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+func Get_token(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputTokenRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+	}
+
+	argc := new(InputTokenReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_token: %+v", argc)
+
+	//检查参数
+	if argc.Token == "" || argc.TokenId == "" {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	token := strings.ToLower(argc.Token)
+
+	//查询数据库
+	owner, err := (&NftOwner{}).FindNftOwner(c.Mysql(), token, argc.TokenId)
+	if err != nil {
+		log.Debugf("FindNftOwner error:%s,token:%s,id:%s", err.Error(), token, argc.TokenId)
+		return c.RESULT_ERROR(NFT_NOT_EXIST, err.Error())
+	}
+	rsp.NftInfo = NftInfo{
+		Token:       owner.F_token,
+		TokenId:     owner.F_token_id,
+		Owner:       owner.F_owner,
+		TokenURI:    owner.F_token_uri,
+		BlockNumber: owner.F_block,
+	}
+
+	contract, err := (&Token{}).FindTokenByAddr(c.Mysql(), token)
+	if err == nil {
+		rsp.Name = contract.F_name
+		rsp.Symbol = contract.F_symbol
+	}
+
+	_, count, err := GetNftTransfers(c.Mysql(), NftFilter{Token: token, TokenId: argc.TokenId}, 0, 0)
+	if err != nil {
+		log.Debugf("GetNftTransfers error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetNftTransfers error:%s", err.Error()))
+	}
+	rsp.Transfers = count
+
+	//返回结果
+	return c.RESULT(rsp)
+}
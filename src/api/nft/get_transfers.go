@@ -0,0 +1,73 @@
+package nft
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+func Get_transfers(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputTransfersRsp{
+		ErrNo:     0,
+		ErrMsg:    "success",
+		Transfers: []TransferInfo{},
+	}
+
+	argc := new(InputTransfersReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_transfers: %+v", argc)
+
+	//检查参数
+	if argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	if argc.TokenId != "" && argc.Token == "" {
+		log.Debugf("token_id without token")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "token_id without token")
+	}
+
+	//查询数据库
+	filter := NftFilter{
+		Token:   strings.ToLower(argc.Token),
+		TokenId: argc.TokenId,
+		Addr:    strings.ToLower(argc.Addr),
+	}
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	transfers, count, err := GetNftTransfers(c.Mysql(), filter, offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetNftTransfers error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetNftTransfers error:%s", err.Error()))
+	}
+	rsp.Count = count
+
+	//包装参数
+	for _, t := range transfers {
+		rsp.Transfers = append(rsp.Transfers, TransferInfo{
+			TxHash:      t.F_tx_hash,
+			LogIndex:    t.F_log_index,
+			BlockNumber: t.F_block,
+			Timestamp:   t.F_timestamp,
+			Token:       t.F_token,
+			TokenId:     t.F_token_id,
+			From:        t.F_from,
+			To:          t.F_to,
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
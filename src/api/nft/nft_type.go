@@ -0,0 +1,65 @@
+package nft
+
+const MAX_PAGE_SIZE = 1000
+
+type InputOwnerReq struct {
+	Owner     string `json:"owner" form:"owner"`
+	Token     string `json:"token" form:"token"` //ERC-721合约地址，空为全部
+	PageIndex int    `json:"pageIndex" form:"pageIndex"`
+	PageSize  int    `json:"pageSize" form:"pageSize"`
+}
+
+type InputTokenReq struct {
+	Token   string `json:"token" form:"token"`       //ERC-721合约地址
+	TokenId string `json:"token_id" form:"token_id"` //十进制
+}
+
+type InputTransfersReq struct {
+	Token     string `json:"token" form:"token"`       //ERC-721合约地址，空为全部
+	TokenId   string `json:"token_id" form:"token_id"` //空为全部
+	Addr      string `json:"addr" form:"addr"`         //转出或转入地址，空为全部
+	PageIndex int    `json:"pageIndex" form:"pageIndex"`
+	PageSize  int    `json:"pageSize" form:"pageSize"`
+}
+
+type NftInfo struct {
+	Token       string `json:"token"`
+	TokenId     string `json:"token_id"`
+	Owner       string `json:"owner"`
+	TokenURI    string `json:"token_uri"`    //空为未知
+	BlockNumber int64  `json:"block_number"` //最新转账的高度
+}
+
+type TransferInfo struct {
+	TxHash      string `json:"tx_hash"`
+	LogIndex    int64  `json:"log_index"`
+	BlockNumber int64  `json:"block_number"`
+	Timestamp   int64  `json:"timestamp"`
+	Token       string `json:"token"`
+	TokenId     string `json:"token_id"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+}
+
+type OutputOwnerRsp struct {
+	ErrNo  int       `json:"err_no"`
+	ErrMsg string    `json:"err_msg"`
+	Count  int64     `json:"count"` //持有个数
+	Nfts   []NftInfo `json:"nfts"`
+}
+
+type OutputTokenRsp struct {
+	ErrNo  int    `json:"err_no"`
+	ErrMsg string `json:"err_msg"`
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+	NftInfo
+	Transfers int64 `json:"transfers"` //转账次数
+}
+
+type OutputTransfersRsp struct {
+	ErrNo     int            `json:"err_no"`
+	ErrMsg    string         `json:"err_msg"`
+	Count     int64          `json:"count"` //转账个数
+	Transfers []TransferInfo `json:"transfers"`
+}
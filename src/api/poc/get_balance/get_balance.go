@@ -5,6 +5,7 @@ import (
 	"go-web3"
 	"go-web3/providers"
 
+	"github.com/EthereumHD/Scan/src/api/contract"
 	. "github.com/EthereumHD/Scan/src/apicontext"
 	"github.com/EthereumHD/Scan/src/config"
 	. "github.com/EthereumHD/Scan/src/const"
@@ -24,6 +25,9 @@ type Output struct {
 	Balance      string `json:"balance"`
 	Transactions int64  `json:"transactions"`
 	MinedBlocks  int64  `json:"mined_blocks"`
+	IsContract   bool   `json:"is_contract"`
+	Label        string `json:"label,omitempty"`    //地址标签
+	Category     string `json:"category,omitempty"` //标签分类
 }
 
 func Main(cc echo.Context) error {
@@ -67,6 +71,20 @@ func Main(cc echo.Context) error {
 	}
 
 	output.Transactions = count
+
+	output.IsContract, err = contract.IsContract(c.Mysql(), webthree, input.Addr)
+	if err != nil {
+		log.Debugf("IsContract error:%s,addr:%s", err.Error(), input.Addr)
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, err.Error())
+	}
+
+	label, err := (&model.AddressLabel{}).FindAddressLabel(c.Mysql(), input.Addr)
+	if err != nil && err.Error() != DATA_NOT_EXIST {
+		return c.RESULT_ERROR(ERR_DATABASE_ERROR, err.Error())
+	}
+	output.Label = label.F_label
+	output.Category = label.F_category
+
 	output.ErrNo = 0
 	output.ErrMsg = "success"
 	output.Balance = bal.String()
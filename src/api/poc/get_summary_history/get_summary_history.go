@@ -0,0 +1,111 @@
+package get_summary_history
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"time"
+)
+
+const MAX_POINTS = 1000
+
+type InputReq struct {
+	Begin    int64  `json:"begin" form:"begin"`       //unix秒，默认end前30天
+	End      int64  `json:"end" form:"end"`           //unix秒，默认当前
+	Interval string `json:"interval" form:"interval"` //hour 或 day
+}
+
+type SummaryPoint struct {
+	Time          int64  `json:"time"` //时间段的起点
+	BlockNumber   int64  `json:"block_number"`
+	Difficulty    string `json:"difficulty"`          //挖矿难度，最近区块的平均
+	Capability    string `json:"capability"`          //算力容量(byte)
+	OnlineMiner   int64  `json:"online_miner"`        //在线矿工数
+	BlockCount24H int64  `json:"block_count_last24h"` //24小时爆块数
+	PbDayReward   string `json:"pb_day_reward"`       //每PB日均收益(wei)
+	TotalRewarded string `json:"total_rewarded"`      //总已挖数量(wei)
+	TotalMortgage string `json:"total_mortgage"`      //总抵押数量(wei)
+}
+
+type OutputRsp struct {
+	ErrNo    int            `json:"err_no"`
+	ErrMsg   string         `json:"err_msg"`
+	Interval int64          `json:"interval"` //秒
+	Points   []SummaryPoint `json:"points"`
+}
+
+// Main the last snapshot of each hour or day in [begin, end)
+func Main(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Points: []SummaryPoint{},
+	}
+
+	argc := new(InputReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_summary_history: %+v", argc)
+
+	//检查参数
+	switch argc.Interval {
+	case "hour":
+		rsp.Interval = 3600
+	case "day", "":
+		rsp.Interval = 86400
+	default:
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "interval should be hour or day")
+	}
+	if argc.End == 0 {
+		argc.End = time.Now().Unix()
+	}
+	if argc.Begin == 0 {
+		argc.Begin = argc.End - 30*86400
+	}
+	if argc.Begin < 0 || argc.Begin >= argc.End || (argc.End-argc.Begin)/rsp.Interval > MAX_POINTS {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	history, err := model.GetSummaryHistory(c.Mysql(), argc.Begin, argc.End)
+	if err != nil {
+		log.Debugf("GetSummaryHistory error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetSummaryHistory error:%s", err.Error()))
+	}
+
+	//包装参数，oldest first so the last one of a period overwrites the others
+	for _, h := range history {
+		point := SummaryPoint{
+			Time:          h.F_timestamp / rsp.Interval * rsp.Interval,
+			BlockNumber:   h.F_block,
+			Difficulty:    h.F_difficulty,
+			Capability:    h.F_capacity,
+			OnlineMiner:   h.F_online_miners,
+			BlockCount24H: h.F_block_count,
+			PbDayReward:   h.F_pb_day_reward,
+			TotalRewarded: h.F_total_rewarded,
+			TotalMortgage: h.F_total_mortgage,
+		}
+		if n := len(rsp.Points); n > 0 && rsp.Points[n-1].Time == point.Time {
+			rsp.Points[n-1] = point
+		} else {
+			rsp.Points = append(rsp.Points, point)
+		}
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
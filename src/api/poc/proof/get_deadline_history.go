@@ -0,0 +1,67 @@
+package proof
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+func Get_deadline_history(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputHistoryRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Points: []DeadlinePoint{},
+	}
+
+	argc := new(InputHistoryReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_deadline_history: %+v", argc)
+
+	//检查参数
+	switch argc.Interval {
+	case "hour":
+		rsp.Interval = 3600
+	case "day", "":
+		rsp.Interval = 86400
+	default:
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "interval should be hour or day")
+	}
+	begin, end, err := timeRange(argc.Begin, argc.End)
+	if err != nil || (end-begin)/rsp.Interval > MAX_POINTS {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	stats, err := GetDeadlineStatsByTime(c.Mysql(), begin, end, rsp.Interval, strings.ToLower(argc.Miner))
+	if err != nil {
+		log.Debugf("GetDeadlineStatsByTime error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetDeadlineStatsByTime error:%s", err.Error()))
+	}
+
+	//包装参数
+	for _, s := range stats {
+		rsp.Points = append(rsp.Points, DeadlinePoint{
+			Time:         s.Time,
+			DeadlineInfo: toDeadlineInfo(s),
+			AvgBlockTime: avgBlockTime(s.Blocks, s.FirstTimestamp, s.LastTimestamp),
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
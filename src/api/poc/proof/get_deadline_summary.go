@@ -0,0 +1,55 @@
+package proof
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+// Get_deadline_summary the average deadline against the target block time
+func Get_deadline_summary(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputSummaryRsp{
+		ErrNo:           0,
+		ErrMsg:          "success",
+		TargetBlockTime: TARGETBLOCKTIME,
+	}
+
+	argc := new(InputRangeReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_deadline_summary: %+v", argc)
+
+	//检查参数
+	begin, end, err := timeRange(argc.Begin, argc.End)
+	if err != nil {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	stats, err := GetDeadlineStats(c.Mysql(), begin, end, strings.ToLower(argc.Miner))
+	if err != nil {
+		log.Debugf("GetDeadlineStats error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetDeadlineStats error:%s", err.Error()))
+	}
+
+	//包装参数
+	rsp.DeadlineInfo = toDeadlineInfo(stats)
+	rsp.AvgBlockTime = avgBlockTime(stats.Blocks, stats.FirstTimestamp, stats.LastTimestamp)
+	rsp.DeadlineRatio = stats.AvgDeadline / float64(TARGETBLOCKTIME)
+
+	//返回结果
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,75 @@
+package proof
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+func Get_deadlines_by_miner(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputMinerRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Miners: []MinerDeadlineInfo{},
+	}
+
+	argc := new(InputMinerReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_deadlines_by_miner: %+v", argc)
+
+	//检查参数
+	begin, end, err := timeRange(argc.Begin, argc.End)
+	if err != nil || argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	network, err := GetDeadlineStats(c.Mysql(), begin, end, "")
+	if err != nil {
+		log.Debugf("GetDeadlineStats error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetDeadlineStats error:%s", err.Error()))
+	}
+
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	stats, count, err := GetDeadlineStatsByMiner(c.Mysql(), begin, end, offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetDeadlineStatsByMiner error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetDeadlineStatsByMiner error:%s", err.Error()))
+	}
+	rsp.Count = count
+
+	//包装参数
+	rsp.Network = toDeadlineInfo(network)
+	for _, s := range stats {
+		miner := MinerDeadlineInfo{Miner: s.F_miner, DeadlineInfo: toDeadlineInfo(s)}
+		if network.AvgDeadline > 0 {
+			miner.Ratio = s.AvgDeadline / network.AvgDeadline
+		}
+		rsp.Miners = append(rsp.Miners, miner)
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
+
+func toDeadlineInfo(s DeadlineStats) DeadlineInfo {
+	return DeadlineInfo{
+		Blocks:      s.Blocks,
+		AvgDeadline: s.AvgDeadline,
+		MinDeadline: s.MinDeadline,
+		MaxDeadline: s.MaxDeadline,
+	}
+}
@@ -0,0 +1,78 @@
+package proof
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+// Get_scoop_distribution the blocks of each scoop group, a healthy plot gives a uniform distribution
+func Get_scoop_distribution(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputScoopRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Scoops: []ScoopInfo{},
+	}
+
+	argc := new(InputScoopReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_scoop_distribution: %+v", argc)
+
+	//检查参数
+	if argc.Buckets == 0 {
+		argc.Buckets = 64
+	}
+	begin, end, err := timeRange(argc.Begin, argc.End)
+	if err != nil || argc.Buckets < 0 || argc.Buckets > SCOOPNUMBER {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	rsp.BucketSize = (SCOOPNUMBER + argc.Buckets - 1) / argc.Buckets
+
+	//查询数据库
+	counts, err := GetScoopDistribution(c.Mysql(), begin, end, rsp.BucketSize, strings.ToLower(argc.Miner))
+	if err != nil {
+		log.Debugf("GetScoopDistribution error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetScoopDistribution error:%s", err.Error()))
+	}
+
+	//包装参数，没有块的分组也返回
+	blocks := make(map[int64]int64)
+	for _, count := range counts {
+		blocks[count.Scoop] = count.Blocks
+		rsp.Blocks += count.Blocks
+	}
+	for scoop := int64(0); scoop < SCOOPNUMBER; scoop += rsp.BucketSize {
+		size := rsp.BucketSize
+		if scoop+size > SCOOPNUMBER {
+			size = SCOOPNUMBER - scoop
+		}
+
+		info := ScoopInfo{
+			Scoop:    scoop,
+			Blocks:   blocks[scoop],
+			Expected: float64(rsp.Blocks) * float64(size) / SCOOPNUMBER,
+		}
+		if info.Expected > 0 {
+			diff := float64(info.Blocks) - info.Expected
+			rsp.ChiSquare += diff * diff / info.Expected
+		}
+		rsp.Scoops = append(rsp.Scoops, info)
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
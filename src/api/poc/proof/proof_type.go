@@ -0,0 +1,120 @@
+package proof
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	MAX_PAGE_SIZE = 1000
+	MAX_RANGE     = 366 * 86400 //查询的时间范围最多一年
+	MAX_POINTS    = 1000        //时间序列最多的点数
+)
+
+// InputRangeReq the blocks mined in [begin, end), unix seconds, the last 24 hours by default
+type InputRangeReq struct {
+	Begin int64  `json:"begin" form:"begin"`
+	End   int64  `json:"end" form:"end"`
+	Miner string `json:"miner" form:"miner"` //为空则统计所有矿工
+}
+
+type InputMinerReq struct {
+	Begin     int64 `json:"begin" form:"begin"`
+	End       int64 `json:"end" form:"end"`
+	PageIndex int   `json:"pageIndex" form:"pageIndex"`
+	PageSize  int   `json:"pageSize" form:"pageSize"`
+}
+
+type InputHistoryReq struct {
+	Begin    int64  `json:"begin" form:"begin"`
+	End      int64  `json:"end" form:"end"`
+	Miner    string `json:"miner" form:"miner"`
+	Interval string `json:"interval" form:"interval"` //hour 或 day
+}
+
+type InputScoopReq struct {
+	Begin   int64  `json:"begin" form:"begin"`
+	End     int64  `json:"end" form:"end"`
+	Miner   string `json:"miner" form:"miner"`
+	Buckets int64  `json:"buckets" form:"buckets"` //分组个数，默认64
+}
+
+type DeadlineInfo struct {
+	Blocks      int64   `json:"blocks"`
+	AvgDeadline float64 `json:"avg_deadline"` //秒
+	MinDeadline string  `json:"min_deadline"`
+	MaxDeadline string  `json:"max_deadline"`
+}
+
+type MinerDeadlineInfo struct {
+	Miner string `json:"miner"`
+	DeadlineInfo
+	Ratio float64 `json:"ratio"` //平均deadline/全网平均deadline，过小的可能有问题
+}
+
+type DeadlinePoint struct {
+	Time int64 `json:"time"` //时间段的起点
+	DeadlineInfo
+	AvgBlockTime float64 `json:"avg_block_time"` //秒，只有一个块时为0
+}
+
+type ScoopInfo struct {
+	Scoop    int64   `json:"scoop"` //分组的第一个scoop
+	Blocks   int64   `json:"blocks"`
+	Expected float64 `json:"expected"` //均匀分布时的块数
+}
+
+type OutputMinerRsp struct {
+	ErrNo   int                 `json:"err_no"`
+	ErrMsg  string              `json:"err_msg"`
+	Count   int64               `json:"count"` //矿工个数
+	Network DeadlineInfo        `json:"network"`
+	Miners  []MinerDeadlineInfo `json:"miners"`
+}
+
+type OutputHistoryRsp struct {
+	ErrNo    int             `json:"err_no"`
+	ErrMsg   string          `json:"err_msg"`
+	Interval int64           `json:"interval"` //秒
+	Points   []DeadlinePoint `json:"points"`
+}
+
+type OutputSummaryRsp struct {
+	ErrNo  int    `json:"err_no"`
+	ErrMsg string `json:"err_msg"`
+	DeadlineInfo
+	AvgBlockTime    float64 `json:"avg_block_time"`    //秒
+	TargetBlockTime int64   `json:"target_block_time"` //秒
+	DeadlineRatio   float64 `json:"deadline_ratio"`    //平均deadline/目标出块时间
+}
+
+type OutputScoopRsp struct {
+	ErrNo      int         `json:"err_no"`
+	ErrMsg     string      `json:"err_msg"`
+	Blocks     int64       `json:"blocks"`
+	BucketSize int64       `json:"bucket_size"`
+	ChiSquare  float64     `json:"chi_square"` //与均匀分布的卡方值，自由度为分组数-1
+	Scoops     []ScoopInfo `json:"scoops"`
+}
+
+// timeRange check [begin, end) and fill the defaults
+func timeRange(begin int64, end int64) (int64, int64, error) {
+	if end == 0 {
+		end = time.Now().Unix()
+	}
+	if begin == 0 {
+		begin = end - 86400
+	}
+	if begin < 0 || begin >= end || end-begin > MAX_RANGE {
+		return 0, 0, errors.New("invalid time range")
+	}
+
+	return begin, end, nil
+}
+
+func avgBlockTime(blocks int64, first int64, last int64) float64 {
+	if blocks < 2 {
+		return 0
+	}
+	return float64(last-first) / float64(blocks-1)
+}
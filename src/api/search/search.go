@@ -0,0 +1,160 @@
+package search
+
+import (
+	"fmt"
+	"github.com/EthereumHD/Scan/src/api/contract"
+	"github.com/EthereumHD/Scan/src/api/transaction/get_hash_pending"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"github.com/labstack/echo"
+	"go-web3"
+	"qoobing.com/utillib.golang/log"
+	"strconv"
+	"strings"
+)
+
+// Main classify q as a block height, block or tx hash, address or text, and look it up. A hash is looked up
+// in t_block, t_transaction and then the txpool of the node, text is matched as the prefix of labels and
+// token symbols or names
+func Main(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputSearchRsp{
+		ErrNo:   0,
+		ErrMsg:  "success",
+		Results: []SearchResult{},
+	}
+
+	argc := new(InputSearchReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Search: %+v", argc)
+
+	//检查参数
+	argc.Q = strings.TrimSpace(argc.Q)
+	if argc.Limit == 0 {
+		argc.Limit = DEFAULT_SEARCH_LIMIT
+	}
+	if argc.Q == "" || argc.Limit < 0 || argc.Limit > MAX_SEARCH_LIMIT {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+	rsp.Query = argc.Q
+	rsp.Kind = classify(argc.Q)
+
+	//查询数据库
+	var err error
+	switch rsp.Kind {
+	case KIND_HEIGHT:
+		rsp.Results, err = searchHeight(c.Mysql(), argc.Q)
+	case KIND_HASH:
+		rsp.Results, err = searchHash(c.Mysql(), strings.ToLower(argc.Q))
+	case KIND_ADDRESS:
+		rsp.Results, err = searchAddress(c.Mysql(), c.Web3(), strings.ToLower(argc.Q))
+	default:
+		rsp.Results, err = searchText(c.Mysql(), argc.Q, argc.Limit)
+	}
+	if err != nil {
+		log.Debugf("Search error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("Search error:%s", err.Error()))
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
+
+// searchHeight the block of the height, none if not synced yet
+func searchHeight(db *gorm.DB, q string) (results []SearchResult, err error) {
+	results = []SearchResult{}
+
+	height, err := strconv.ParseInt(q, 10, 64)
+	if err != nil {
+		return results, nil
+	}
+	block, err := (&Block{}).FindBlockByHeight(db, height)
+	if err != nil {
+		return results, nil
+	}
+
+	return append(results, SearchResult{Type: TYPE_BLOCK, Value: block.F_hash, BlockNumber: block.F_block}), nil
+}
+
+// searchHash the block or transaction of the hash, the txpool of the node is asked only if neither is indexed
+func searchHash(db *gorm.DB, hash string) (results []SearchResult, err error) {
+	results = []SearchResult{}
+
+	if block, err := (&Block{}).FindBlockByHash(db, hash); err == nil {
+		results = append(results, SearchResult{Type: TYPE_BLOCK, Value: block.F_hash, BlockNumber: block.F_block})
+	}
+	if tx, err := (&Transaction{}).FindTrasactionByHash(db, hash); err == nil {
+		results = append(results, SearchResult{Type: TYPE_TRANSACTION, Value: tx.F_tx_hash, BlockNumber: tx.F_block})
+	}
+	if len(results) > 0 {
+		return results, nil
+	}
+
+	pending, found, err := get_hash_pending.FindPending(hash)
+	if err != nil {
+		//the node being unreachable is not a failure of the search
+		log.Debugf("FindPending error:%s", err.Error())
+		return results, nil
+	}
+	if found {
+		results = append(results, SearchResult{Type: TYPE_PENDING_TRANSACTION, Value: pending.TxHash})
+	}
+
+	return results, nil
+}
+
+// searchAddress any well-formed address is a result, with its label and token if it has
+func searchAddress(db *gorm.DB, webthree *web3.Web3, addr string) (results []SearchResult, err error) {
+	result := SearchResult{Type: TYPE_ADDRESS, Value: addr}
+
+	if label, err := (&AddressLabel{}).FindAddressLabel(db, addr); err == nil {
+		result.Label = label.F_label
+		result.Category = label.F_category
+	}
+	if token, err := (&Token{}).FindTokenByAddr(db, addr); err == nil {
+		result.Name = token.F_name
+		result.Symbol = token.F_symbol
+		result.TokenType = token.F_type
+	}
+	if result.IsContract, err = contract.IsContract(db, webthree, addr); err != nil {
+		return nil, err
+	}
+
+	return []SearchResult{result}, nil
+}
+
+// searchText the labels, then the tokens, whose label, symbol or name starts with q
+func searchText(db *gorm.DB, q string, limit int) (results []SearchResult, err error) {
+	results = []SearchResult{}
+
+	labels, err := SearchAddressLabels(db, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range labels {
+		results = append(results, SearchResult{Type: TYPE_LABEL, Value: label.F_addr, Label: label.F_label,
+			Category: label.F_category})
+	}
+
+	tokens, err := SearchTokens(db, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		results = append(results, SearchResult{Type: TYPE_TOKEN, Value: token.F_address, Name: token.F_name,
+			Symbol: token.F_symbol, TokenType: token.F_type})
+	}
+
+	return results, nil
+}
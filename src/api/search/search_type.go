@@ -0,0 +1,73 @@
+package search
+
+import (
+	"regexp"
+)
+
+const (
+	DEFAULT_SEARCH_LIMIT = 10
+	MAX_SEARCH_LIMIT     = 50
+)
+
+// the kinds of query
+const (
+	KIND_HEIGHT  = "height"
+	KIND_HASH    = "hash"
+	KIND_ADDRESS = "address"
+	KIND_TEXT    = "text"
+)
+
+// the types of result
+const (
+	TYPE_BLOCK               = "block"
+	TYPE_TRANSACTION         = "transaction"
+	TYPE_PENDING_TRANSACTION = "pending_transaction"
+	TYPE_ADDRESS             = "address"
+	TYPE_LABEL               = "label"
+	TYPE_TOKEN               = "token"
+)
+
+var (
+	heightPattern  = regexp.MustCompile(`^[0-9]{1,18}$`)
+	hashPattern    = regexp.MustCompile(`^0[xX][0-9a-fA-F]{64}$`)
+	addressPattern = regexp.MustCompile(`^0[xX][0-9a-fA-F]{40}$`)
+)
+
+type InputSearchReq struct {
+	Q     string `json:"q" form:"q" query:"q"`
+	Limit int    `json:"limit" form:"limit" query:"limit"` //label和token前缀匹配的条数，默认10，最多50
+}
+
+type SearchResult struct {
+	Type        string `json:"type"`  //block，transaction，pending_transaction，address，label，token
+	Value       string `json:"value"` //区块高度、区块或交易hash、地址
+	BlockNumber int64  `json:"block_number,omitempty"`
+	Label       string `json:"label,omitempty"`
+	Category    string `json:"category,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Symbol      string `json:"symbol,omitempty"`
+	TokenType   string `json:"token_type,omitempty"`
+	IsContract  bool   `json:"is_contract,omitempty"`
+}
+
+type OutputSearchRsp struct {
+	ErrNo   int            `json:"err_no"`
+	ErrMsg  string         `json:"err_msg"`
+	Query   string         `json:"query"`
+	Kind    string         `json:"kind"` //height，hash，address，text
+	Results []SearchResult `json:"results"`
+}
+
+// classify the kind of a trimmed query
+func classify(q string) string {
+	switch {
+	case heightPattern.MatchString(q):
+		return KIND_HEIGHT
+	case hashPattern.MatchString(q):
+		return KIND_HASH
+	case addressPattern.MatchString(q):
+		return KIND_ADDRESS
+	default:
+		return KIND_TEXT
+	}
+}
@@ -0,0 +1,41 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	address := strings.Repeat("cd", 20)
+
+	tests := []struct {
+		Query    string
+		Expected string
+	}{
+		{"0", KIND_HEIGHT},
+		{"123456", KIND_HEIGHT},
+		{"999999999999999999", KIND_HEIGHT},
+		{"1000000000000000000", KIND_TEXT},
+		{"-1", KIND_TEXT},
+		{"12a", KIND_TEXT},
+		{"0x" + hash, KIND_HASH},
+		{"0X" + strings.ToUpper(hash), KIND_HASH},
+		{hash, KIND_TEXT},
+		{"0x" + hash[:63], KIND_TEXT},
+		{"0x" + hash + "0", KIND_TEXT},
+		{"0x" + address, KIND_ADDRESS},
+		{"0X" + strings.ToUpper(address), KIND_ADDRESS},
+		{address, KIND_TEXT},
+		{"0x" + address[:39] + "g", KIND_TEXT},
+		{"0x", KIND_TEXT},
+		{"USD", KIND_TEXT},
+		{"", KIND_TEXT},
+	}
+
+	for _, tc := range tests {
+		if got := classify(tc.Query); got != tc.Expected {
+			t.Errorf("classify(%q): got %s; want %s", tc.Query, got, tc.Expected)
+		}
+	}
+}
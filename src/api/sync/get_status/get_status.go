@@ -0,0 +1,65 @@
+package get_status
+
+import (
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/EthereumHD/Scan/src/sync"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+type Output struct {
+	ErrNo         int            `json:"err_no"`
+	ErrMsg        string         `json:"err_msg"`
+	Height        int64          `json:"height"`          //最后提交的区块高度
+	Hash          string         `json:"hash"`            //最后提交的区块hash
+	Head          int64          `json:"head"`            //最后看到的链上高度
+	HeadTime      int64          `json:"head_time"`       //最后看到链上高度的时间
+	Behind        int64          `json:"behind"`          //落后链上的区块数
+	ReorgCount    int64          `json:"reorg_count"`     //分叉回退次数
+	LastReorgTime int64          `json:"last_reorg_time"` //最后一次分叉回退时间
+	ErrorCount    int64          `json:"error_count"`     //同步出错次数
+	LastError     string         `json:"last_error"`      //最后一次错误
+	LastErrorTime int64          `json:"last_error_time"` //最后一次错误时间
+	Throughput    sync.SyncStats `json:"throughput"`      //同步速度
+}
+
+func Main(cc echo.Context) error {
+
+	//Step 1. init x
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+	var (
+		output Output
+	)
+
+	//Step 3. get sync state
+	state, err := (&model.SyncState{}).FindSyncState(c.Mysql(), model.SYNC_STATE_LIVE)
+	if err != nil {
+		log.Debugf("FindSyncState error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, err.Error())
+	}
+
+	output.ErrNo = 0
+	output.ErrMsg = "success"
+	output.Height = state.F_height
+	output.Hash = state.F_hash
+	output.Head = state.F_head
+	output.HeadTime = state.F_head_time
+	output.ReorgCount = state.F_reorg_count
+	output.LastReorgTime = state.F_last_reorg_time
+	output.ErrorCount = state.F_error_count
+	output.LastError = state.F_last_error
+	output.LastErrorTime = state.F_last_error_time
+	output.Throughput = sync.Stats()
+
+	if state.F_head > state.F_height {
+		output.Behind = state.F_head - state.F_height
+	}
+
+	return c.RESULT(output)
+}
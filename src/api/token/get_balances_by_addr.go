@@ -0,0 +1,70 @@
+package token
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+func Get_balances_by_addr(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputBalancesRsp{
+		ErrNo:    0,
+		ErrMsg:   "success",
+		Balances: []BalanceInfo{},
+	}
+
+	argc := new(InputAddrReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_balances_by_addr: %+v", argc)
+
+	//检查参数
+	if argc.Addr == "" {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	balances, err := GetTokenBalancesByAddr(c.Mysql(), strings.ToLower(argc.Addr))
+	if err != nil {
+		log.Debugf("GetTokenBalancesByAddr error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTokenBalancesByAddr error:%s", err.Error()))
+	}
+
+	addrs := make([]string, 0, len(balances))
+	for _, b := range balances {
+		addrs = append(addrs, b.F_token)
+	}
+	tokens, err := GetTokensByAddrs(c.Mysql(), addrs)
+	if err != nil {
+		log.Debugf("GetTokensByAddrs error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTokensByAddrs error:%s", err.Error()))
+	}
+
+	//包装参数
+	for _, b := range balances {
+		t := tokens[b.F_token]
+		rsp.Balances = append(rsp.Balances, BalanceInfo{
+			Token:    b.F_token,
+			Name:     t.F_name,
+			Symbol:   t.F_symbol,
+			Decimals: t.F_decimals,
+			Balance:  b.F_balance,
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
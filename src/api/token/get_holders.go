@@ -0,0 +1,58 @@
+package token
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+func Get_holders(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputHoldersRsp{
+		ErrNo:   0,
+		ErrMsg:  "success",
+		Holders: []HolderInfo{},
+	}
+
+	argc := new(InputTokenReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_holders: %+v", argc)
+
+	//检查参数
+	if argc.Token == "" || argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	holders, count, err := GetTokenHolders(c.Mysql(), strings.ToLower(argc.Token), offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetTokenHolders error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTokenHolders error:%s", err.Error()))
+	}
+	rsp.Count = count
+
+	//包装参数
+	for _, h := range holders {
+		rsp.Holders = append(rsp.Holders, HolderInfo{
+			Holder:  h.F_holder,
+			Balance: h.F_balance,
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,77 @@
+package token
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+)
+
+func Get_list(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputListRsp{
+		ErrNo:  0,
+		ErrMsg: "success",
+		Tokens: []TokenInfo{},
+	}
+
+	argc := new(InputReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_list: %+v", argc)
+
+	//检查参数
+	if argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	count, err := GetTokenNum(c.Mysql(), TOKEN_TYPE_ERC20)
+	if err != nil {
+		log.Debugf("GetTokenNum error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTokenNum error:%s", err.Error()))
+	}
+	rsp.Count = count
+
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	tokens, err := GetTokens(c.Mysql(), TOKEN_TYPE_ERC20, offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetTokens error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTokens error:%s", err.Error()))
+	}
+
+	addrs := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		addrs = append(addrs, t.F_address)
+	}
+	holders, err := GetTokenHolderCounts(c.Mysql(), addrs)
+	if err != nil {
+		log.Debugf("GetTokenHolderCounts error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTokenHolderCounts error:%s", err.Error()))
+	}
+
+	//包装参数
+	for _, t := range tokens {
+		rsp.Tokens = append(rsp.Tokens, TokenInfo{
+			Address:     t.F_address,
+			Name:        t.F_name,
+			Symbol:      t.F_symbol,
+			Decimals:    t.F_decimals,
+			TotalSupply: t.F_total_supply,
+			Holders:     holders[t.F_address],
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
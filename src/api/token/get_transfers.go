@@ -0,0 +1,64 @@
+package token
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/labstack/echo"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+func Get_transfers(cc echo.Context) error {
+	c := cc.(ApiContext)
+	defer c.PANIC_RECOVER()
+	c.Mysql()
+
+	//Step 2. parameters initial
+
+	rsp := OutputTransfersRsp{
+		ErrNo:     0,
+		ErrMsg:    "success",
+		Transfers: []TransferInfo{},
+	}
+
+	argc := new(InputTransfersReq)
+
+	if err := c.BindInput(argc); err != nil {
+		return c.RESULT_PARAMETER_ERROR(err.Error())
+	}
+	log.Debugf("receive Get_transfers: %+v", argc)
+
+	//检查参数
+	if argc.PageIndex < 1 || argc.PageSize <= 0 || argc.PageSize > MAX_PAGE_SIZE {
+		log.Debugf("param error")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
+	}
+
+	//查询数据库
+	offset := (argc.PageIndex - 1) * argc.PageSize
+	transfers, count, err := GetTokenTransfers(c.Mysql(), strings.ToLower(argc.Token), strings.ToLower(argc.Addr), offset, argc.PageSize)
+	if err != nil {
+		log.Debugf("GetTokenTransfers error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("GetTokenTransfers error:%s", err.Error()))
+	}
+	rsp.Count = count
+
+	//包装参数
+	for _, t := range transfers {
+		rsp.Transfers = append(rsp.Transfers, TransferInfo{
+			TxHash:      t.F_tx_hash,
+			LogIndex:    t.F_log_index,
+			BlockNumber: t.F_block,
+			Timestamp:   t.F_timestamp,
+			Token:       t.F_token,
+			From:        t.F_from,
+			To:          t.F_to,
+			Value:       t.F_value,
+		})
+	}
+
+	//返回结果
+	return c.RESULT(rsp)
+}
@@ -0,0 +1,85 @@
+package token
+
+const MAX_PAGE_SIZE = 1000
+
+type InputReq struct {
+	PageIndex int `json:"pageIndex" form:"pageIndex"` //范围起点
+	PageSize  int `json:"pageSize" form:"pageSize"`   //范围重点
+}
+
+type InputTokenReq struct {
+	Token     string `json:"token" form:"token"` //代币合约地址
+	PageIndex int    `json:"pageIndex" form:"pageIndex"`
+	PageSize  int    `json:"pageSize" form:"pageSize"`
+}
+
+type InputTransfersReq struct {
+	Token     string `json:"token" form:"token"` //代币合约地址，空为全部
+	Addr      string `json:"addr" form:"addr"`   //转出或转入地址，空为全部
+	PageIndex int    `json:"pageIndex" form:"pageIndex"`
+	PageSize  int    `json:"pageSize" form:"pageSize"`
+}
+
+type InputAddrReq struct {
+	Addr string `json:"addr" form:"addr"`
+}
+
+type TokenInfo struct {
+	Address     string `json:"address"`
+	Name        string `json:"name"`
+	Symbol      string `json:"symbol"`
+	Decimals    int    `json:"decimals"`     //-1 未知
+	TotalSupply string `json:"total_supply"` //空为未知
+	Holders     int64  `json:"holders"`
+}
+
+type TransferInfo struct {
+	TxHash      string `json:"tx_hash"`
+	LogIndex    int64  `json:"log_index"`
+	BlockNumber int64  `json:"block_number"`
+	Timestamp   int64  `json:"timestamp"`
+	Token       string `json:"token"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Value       string `json:"value"`
+}
+
+type HolderInfo struct {
+	Holder  string `json:"holder"`
+	Balance string `json:"balance"`
+}
+
+type BalanceInfo struct {
+	Token    string `json:"token"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+	Balance  string `json:"balance"`
+}
+
+type OutputListRsp struct {
+	ErrNo  int         `json:"err_no"`
+	ErrMsg string      `json:"err_msg"`
+	Count  int64       `json:"count"` //代币个数
+	Tokens []TokenInfo `json:"tokens"`
+}
+
+type OutputTransfersRsp struct {
+	ErrNo     int            `json:"err_no"`
+	ErrMsg    string         `json:"err_msg"`
+	Count     int64          `json:"count"` //转账个数
+	Transfers []TransferInfo `json:"transfers"`
+}
+
+type OutputHoldersRsp struct {
+	ErrNo   int          `json:"err_no"`
+	ErrMsg  string       `json:"err_msg"`
+	Count   int64        `json:"count"` //持有者个数
+	Holders []HolderInfo `json:"holders"`
+}
+
+type OutputBalancesRsp struct {
+	ErrNo    int           `json:"err_no"`
+	ErrMsg   string        `json:"err_msg"`
+	Balances []BalanceInfo `json:"balances"`
+}
@@ -1,10 +1,12 @@
 package get_addr_pending
 
 import (
+	"fmt"
 	"github.com/labstack/echo"
 	//"time"
 	"github.com/EthereumHD/Scan/src/api/transaction"
 	. "github.com/EthereumHD/Scan/src/apicontext"
+	. "github.com/EthereumHD/Scan/src/const"
 	"github.com/EthereumHD/Scan/src/config"
 	"go-web3"
 	"go-web3/providers"
@@ -96,6 +98,11 @@ func Main(cc echo.Context) error {
 	//
 	//	output.PenddingList = append(output.PenddingList, trans)
 	//}
+	if err := transaction.SetLabels(c.Mysql(), output.PenddingList); err != nil {
+		log.Debugf("SetLabels error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("SetLabels error:%s", err.Error()))
+	}
+
 	output.ErrNo = 0
 	output.ErrMsg = "success"
 
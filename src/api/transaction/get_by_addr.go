@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"github.com/EthereumHD/Scan/src/api/contract"
 	. "github.com/EthereumHD/Scan/src/apicontext"
 	. "github.com/EthereumHD/Scan/src/const"
 	. "github.com/EthereumHD/Scan/src/model"
@@ -32,6 +33,10 @@ func Get_by_addr(cc echo.Context) error {
 		log.Debugf("param error")
 		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, "param error")
 	}
+	if argc.Internal && argc.PageIndex > MAX_INTERNAL_MERGE_ROWS/argc.PageSize {
+		log.Debugf("param error,page too deep with internal")
+		return c.RESULT_ERROR(ERR_PARAMETER_INVALID, fmt.Sprintf("param error,pageIndex*pageSize over %d with internal", MAX_INTERNAL_MERGE_ROWS))
+	}
 	//查询数据库
 	//sql := "(F_from = '" + argc.Addr + "' or F_to = '" + argc.Addr + "') "
 	count, err := GetTransactionsCountByAddr(c.Mysql(), argc.Addr)
@@ -41,8 +46,27 @@ func Get_by_addr(cc echo.Context) error {
 	}
 	rsp.Count = count
 
+	if argc.Internal {
+		count, err = GetInternalTxsCountByAddr(c.Mysql(), argc.Addr)
+		if err != nil {
+			log.Debugf("GetInternalTxsCountByAddr error:%s,addr:%s", err.Error(), argc.Addr)
+			return c.RESULT_ERROR(TRANSACTION_COUNT_ERROR, fmt.Sprintf("GetInternalTxsCountByAddr error:%s,addr:%s", err.Error(), argc.Addr))
+		}
+		rsp.Count += count
+	}
+
+	rsp.IsContract, err = contract.IsContract(c.Mysql(), c.Web3(), argc.Addr)
+	if err != nil {
+		log.Debugf("IsContract error:%s,addr:%s", err.Error(), argc.Addr)
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, err.Error())
+	}
+
 	offset := (argc.PageIndex - 1) * argc.PageSize
 	size := argc.PageSize
+	if argc.Internal {
+		//both lists up to the end of the page, merged below
+		offset, size = 0, offset+size
+	}
 	transList, err := GetTransactionsByAddr(c.Mysql(), argc.Addr, offset, size)
 	if err != nil {
 		log.Debugf("GetTransactionsByAddr error:%s,addr:%s", err.Error(), argc.Addr)
@@ -58,9 +82,61 @@ func Get_by_addr(cc echo.Context) error {
 		transInfo.Value = trans.F_value
 		transInfo.TxFee = trans.F_tx_fee
 		transInfo.Timestamp = trans.F_timestamp
+		transInfo.Nonce = trans.F_nonce
 		rsp.Transactions = append(rsp.Transactions, transInfo)
 	}
 
+	if argc.Internal {
+		internals, err := GetInternalTxsByAddr(c.Mysql(), argc.Addr, offset, size)
+		if err != nil {
+			log.Debugf("GetInternalTxsByAddr error:%s,addr:%s", err.Error(), argc.Addr)
+			return c.RESULT_ERROR(GET_TRANSACTIONS_ERROR, fmt.Sprintf("GetInternalTxsByAddr error:%s,addr:%s", err.Error(), argc.Addr))
+		}
+		rsp.Transactions = mergeInternalTxs(rsp.Transactions, internals, (argc.PageIndex-1)*argc.PageSize, argc.PageSize)
+	}
+
+	if err := SetLabels(c.Mysql(), rsp.Transactions); err != nil {
+		log.Debugf("SetLabels error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("SetLabels error:%s", err.Error()))
+	}
+
 	//返回结果
 	return c.RESULT(rsp)
 }
+
+// mergeInternalTxs merge the internal calls into the transactions, both newest first, and cut the page out
+func mergeInternalTxs(transList TransList, internals []InternalTx, offset int, size int) TransList {
+	merged := make(TransList, 0, len(transList)+len(internals))
+	i, j := 0, 0
+	for i < len(transList) || j < len(internals) {
+		if j == len(internals) || (i < len(transList) && transList[i].Timestamp >= internals[j].F_timestamp) {
+			merged = append(merged, transList[i])
+			i++
+			continue
+		}
+
+		internal := internals[j]
+		merged = append(merged, TransInfo{
+			TXHash:      internal.F_tx_hash,
+			BlockNumber: internal.F_block,
+			Timestamp:   internal.F_timestamp,
+			From:        internal.F_from,
+			To:          internal.F_to,
+			Value:       internal.F_value,
+			TxFee:       "0",
+			Internal:    true,
+			CallType:    internal.F_type,
+			Depth:       internal.F_depth,
+			Error:       internal.F_error,
+		})
+		j++
+	}
+
+	if offset >= len(merged) {
+		return TransList{}
+	}
+	if offset+size > len(merged) {
+		return merged[offset:]
+	}
+	return merged[offset : offset+size]
+}
@@ -93,6 +93,7 @@ func Get_by_addr_and_type(cc echo.Context) error {
 		transInfo.Value = trans.F_value
 		transInfo.TxFee = trans.F_tx_fee
 		transInfo.Timestamp = trans.F_timestamp
+		transInfo.Nonce = trans.F_nonce
 		transInfo.TxTypeExt = trans.F_tx_type_ext
 		if trans.F_tx_type == 0 && strings.ToUpper(trans.F_from) == strings.ToUpper(argc.Addr) {
 			transInfo.TxType = TX_TYPE_FROM_ME
@@ -106,6 +107,11 @@ func Get_by_addr_and_type(cc echo.Context) error {
 		rsp.Transactions = append(rsp.Transactions, transInfo)
 	}
 
+	if err := SetLabels(c.Mysql(), rsp.Transactions); err != nil {
+		log.Debugf("SetLabels error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("SetLabels error:%s", err.Error()))
+	}
+
 	//返回结果
 	return c.RESULT(rsp)
 }
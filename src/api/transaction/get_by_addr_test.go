@@ -0,0 +1,65 @@
+package transaction
+
+import (
+	"testing"
+
+	. "github.com/EthereumHD/Scan/src/model"
+)
+
+func TestMergeInternalTxs(t *testing.T) {
+	trans := func(hash string, timestamp int64) TransInfo {
+		return TransInfo{TXHash: hash, Timestamp: timestamp}
+	}
+	internal := func(hash string, timestamp int64) InternalTx {
+		return InternalTx{F_tx_hash: hash, F_timestamp: timestamp, F_type: "CALL", F_value: "1"}
+	}
+
+	transList := TransList{trans("t5", 50), trans("t3", 30), trans("t1", 10)}
+	internals := []InternalTx{internal("i4", 40), internal("i3", 30), internal("i0", 0)}
+
+	tests := []struct {
+		name   string
+		offset int
+		size   int
+		want   []string
+	}{
+		{"first page", 0, 3, []string{"t5", "i4", "t3"}},
+		{"same timestamp keeps the transaction first", 2, 2, []string{"t3", "i3"}},
+		{"last page is short", 4, 10, []string{"t1", "i0"}},
+		{"everything", 0, 6, []string{"t5", "i4", "t3", "i3", "t1", "i0"}},
+		{"past the end", 6, 3, []string{}},
+	}
+
+	for _, test := range tests {
+		merged := mergeInternalTxs(transList, internals, test.offset, test.size)
+		if len(merged) != len(test.want) {
+			t.Errorf("%s: got %d rows, want %d", test.name, len(merged), len(test.want))
+			continue
+		}
+		for i, hash := range test.want {
+			if merged[i].TXHash != hash {
+				t.Errorf("%s: row %d is %s, want %s", test.name, i, merged[i].TXHash, hash)
+			}
+			if merged[i].Internal != (hash[0] == 'i') {
+				t.Errorf("%s: row %d internal=%t", test.name, i, merged[i].Internal)
+			}
+		}
+	}
+}
+
+func TestMergeInternalTxsOneSideEmpty(t *testing.T) {
+	internals := []InternalTx{{F_tx_hash: "i2", F_timestamp: 20, F_type: "CREATE", F_depth: 2, F_error: "out of gas"}}
+
+	merged := mergeInternalTxs(TransList{}, internals, 0, 10)
+	if len(merged) != 1 {
+		t.Fatalf("got %d rows, want 1", len(merged))
+	}
+	if row := merged[0]; !row.Internal || row.CallType != "CREATE" || row.Depth != 2 || row.Error != "out of gas" || row.TxFee != "0" {
+		t.Errorf("internal row: %+v", row)
+	}
+
+	merged = mergeInternalTxs(TransList{{TXHash: "t1", Timestamp: 10}}, nil, 0, 10)
+	if len(merged) != 1 || merged[0].Internal {
+		t.Errorf("transactions only: %+v", merged)
+	}
+}
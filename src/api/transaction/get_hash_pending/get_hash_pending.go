@@ -54,27 +54,41 @@ func Main(cc echo.Context) error {
 		return c.RESULT_PARAMETER_ERROR(err.Error())
 	}
 
-	webthree := web3.NewWeb3(providers.NewHTTPProvider(config.Config().Gate, config.Config().TimeOut.RPCTimeOut, false))
-	content, err := webthree.Txpool.Content()
+	detail, found, err := FindPending(input.Hash)
 	if err != nil {
 		log.Fatalf("Content error:%s", err.Error())
 		return err
 	}
+	if found {
+		output.TransactionDetail = detail
+	}
+
+	output.ErrNo = 0
+	output.ErrMsg = "success"
+
+	return c.RESULT(output)
+}
+
+// FindPending look the hash up in the pending transactions of the node's txpool
+func FindPending(hash string) (detail TransactionDetail, found bool, err error) {
+	webthree := web3.NewWeb3(providers.NewHTTPProvider(config.Config().Gate, config.Config().TimeOut.RPCTimeOut, false))
+	content, err := webthree.Txpool.Content()
+	if err != nil {
+		return detail, false, err
+	}
 
 	for _, txmap := range content.Pending {
 		for _, tx := range txmap {
-			if tx.Hash == strings.ToLower(input.Hash) {
-				output.TxHash = input.Hash
-				output.From = tx.From
-				output.To = tx.To
-				output.Value = tx.Value.String()
-				output.Nonce = tx.Nonce.Int64()
+			if tx.Hash == strings.ToLower(hash) {
+				detail.TxHash = hash
+				detail.From = tx.From
+				detail.To = tx.To
+				detail.Value = tx.Value.String()
+				detail.Nonce = tx.Nonce.Int64()
+				found = true
 			}
 		}
 	}
 
-	output.ErrNo = 0
-	output.ErrMsg = "success"
-
-	return c.RESULT(output)
+	return detail, found, nil
 }
@@ -4,6 +4,7 @@ import (
 	. "github.com/EthereumHD/Scan/src/apicontext"
 	"github.com/EthereumHD/Scan/src/config"
 	"github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
 	"github.com/labstack/echo"
 	"go-web3"
 	"go-web3/providers"
@@ -30,6 +31,7 @@ type TransactionDetail struct {
 	Nonce            int64  `json:"nonce"`
 	TransactionIndex int64  `json:"transaction_index"`
 	InputData        string `json:"input_data"`
+	ContractAddress  string `json:"contract_address"`
 }
 
 type Output struct {
@@ -55,6 +57,39 @@ func Main(cc echo.Context) error {
 		return c.RESULT_PARAMETER_ERROR(err.Error())
 	}
 
+	//get transcation from databases, the chain only for the ones not indexed yet
+	databases_trans, err := (&model.Transaction{}).FindTrasactionByHash(c.Mysql(), input.Hash)
+	if err == nil && databases_trans.F_status == _const.NORMAL && databases_trans.HasDetail() {
+		log.Debugf("FindTrasactionByHash success,transcation%+v", databases_trans)
+
+		gasUsed, _ := big.NewInt(0).SetString(databases_trans.F_gas_used, 10)
+		gasPrice, _ := big.NewInt(0).SetString(databases_trans.F_gas_price, 10)
+		if gasUsed == nil || gasPrice == nil {
+			return c.RESULT_ERROR(_const.ERR_DATABASE_ERROR, "invalid gas of transaction:"+input.Hash)
+		}
+
+		output.TxHash = databases_trans.F_tx_hash
+		output.TxReceiptStatus = databases_trans.F_receipt_status == 1
+		output.Height = databases_trans.F_block
+		output.TimeStamp = databases_trans.F_timestamp
+		output.From = databases_trans.F_from
+		output.To = databases_trans.F_to
+		output.Value = databases_trans.F_value
+		output.GasLimit = databases_trans.F_gas
+		output.GasUsedByTx = databases_trans.F_gas_used
+		output.GasPrice = databases_trans.F_gas_price
+		output.ActualTxCost = big.NewInt(0).Mul(gasUsed, gasPrice).String()
+		output.Nonce = databases_trans.F_nonce
+		output.InputData = databases_trans.F_input
+		output.TransactionIndex = databases_trans.F_tx_index
+		output.ContractAddress = databases_trans.F_contract_address
+
+		output.ErrNo = 0
+		output.ErrMsg = "success"
+
+		return c.RESULT(output)
+	}
+
 	//get transcation from chain
 	webthree := web3.NewWeb3(providers.NewHTTPProvider(config.Config().Gate, config.Config().TimeOut.RPCTimeOut, false))
 
@@ -90,7 +125,7 @@ func Main(cc echo.Context) error {
 	log.Debugf("GetTransactionReceipt success")
 
 	output.TxHash = input.Hash
-	output.TxReceiptStatus = true
+	output.TxReceiptStatus = receipt.Status.Int64() == 1
 	output.Height = transaction.BlockNumber.Int64()
 	output.TimeStamp = blockbynumber.Timestamp.Int64()
 	output.From = transaction.From
@@ -103,6 +138,7 @@ func Main(cc echo.Context) error {
 	output.Nonce = transaction.Nonce.Int64()
 	output.InputData = transaction.Input
 	output.TransactionIndex = transaction.TransactionIndex.Int64()
+	output.ContractAddress = receipt.ContractAddress
 
 	// return
 	output.ErrNo = 0
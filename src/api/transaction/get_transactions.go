@@ -57,9 +57,15 @@ func Get_transactions(cc echo.Context) error {
 		transInfo.Value = trans.F_value
 		transInfo.TxFee = trans.F_tx_fee
 		transInfo.Timestamp = trans.F_timestamp
+		transInfo.Nonce = trans.F_nonce
 		rsp.Transactions = append(rsp.Transactions,transInfo)
 	}
 
+	if err := SetLabels(c.Mysql(), rsp.Transactions); err != nil {
+		log.Debugf("SetLabels error:%s", err.Error())
+		return c.RESULT_ERROR(ERR_DATABASE_SELECT_ERROR, fmt.Sprintf("SetLabels error:%s", err.Error()))
+	}
+
 	//返回结果
 	return c.RESULT(rsp)
 }
@@ -0,0 +1,26 @@
+package transaction
+
+import (
+	. "github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"strings"
+)
+
+// SetLabels fill the labels of the senders and receivers of the list
+func SetLabels(db *gorm.DB, transList TransList) error {
+	addrs := make([]string, 0, len(transList)*2)
+	for _, trans := range transList {
+		addrs = append(addrs, trans.From, trans.To)
+	}
+
+	labels, err := GetAddressLabels(db, addrs)
+	if err != nil {
+		return err
+	}
+	for i := range transList {
+		transList[i].FromLabel = labels[strings.ToLower(transList[i].From)].F_label
+		transList[i].ToLabel = labels[strings.ToLower(transList[i].To)].F_label
+	}
+
+	return nil
+}
@@ -1,5 +1,9 @@
 package transaction
 
+// MAX_INTERNAL_MERGE_ROWS the deepest row of a page with internal=true, both lists are read from the
+// newest row down to the end of the page to merge them
+const MAX_INTERNAL_MERGE_ROWS = 10000
+
 type InputReq struct {
 	PageIndex int `json:"pageIndex" form:"pageIndex"` //范围起点
 	PageSize  int `json:"pageSize" form:"pageSize"`   //范围重点
@@ -17,12 +21,18 @@ type TransInfo struct {
 	BlockNumber int64  `json:"block_number"`
 	Timestamp   int64  `json:"timestamp"`
 	From        string `json:"from"`
+	FromLabel   string `json:"from_label,omitempty"` //地址标签
 	To          string `json:"to"`
+	ToLabel     string `json:"to_label,omitempty"`
 	Value       string `json:"value"`
 	TxFee       string `json:"txfee"`
 	Nonce       int64  `json:"nonce"`
 	TxType      int64  `json:"tx_type"`
 	TxTypeExt   string `json:"tx_type_ext"`
+	Internal    bool   `json:"internal,omitempty"`  //内部交易，TXHash为所属交易
+	CallType    string `json:"call_type,omitempty"` //内部交易的调用类型
+	Depth       int64  `json:"depth,omitempty"`     //内部交易的调用深度
+	Error       string `json:"error,omitempty"`     //内部交易失败原因
 }
 
 type TransList []TransInfo
@@ -31,12 +41,14 @@ type InputAddrReq struct {
 	Addr      string `json:"addr" form:"addr"`
 	PageIndex int    `json:"pageIndex" form:"pageIndex"` //范围起点
 	PageSize  int    `json:"pageSize" form:"pageSize"`   //范围重点
+	Internal  bool   `json:"internal" form:"internal"`   //合并转移了value的内部交易，只能翻到前MAX_INTERNAL_MERGE_ROWS条
 }
 
 type OutputAddrRsp struct {
 	ErrNo        int       `json:"err_no"`
 	ErrMsg       string    `json:"err_msg"`
 	Count        int64     `json:"count"` //个数
+	IsContract   bool      `json:"is_contract"`
 	Transactions TransList `json:"transactions"`
 }
 
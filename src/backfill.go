@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/EthereumHD/Scan/src/config"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/EthereumHD/Scan/src/sync"
+	"qoobing.com/utillib.golang/log"
+)
+
+// backfill is `scan backfill --from N --to M --workers K`, it can run next to the serving scan
+func backfill(args []string) int {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	from := flags.Int64("from", 0, "first height to index")
+	to := flags.Int64("to", -1, "last height to index, -1 for the live syncer height, which also caps it")
+	workers := flags.Int("workers", config.Config().Sync.Workers, "chunks indexed in parallel")
+	chunk := flags.Int64("chunk", 1000, "heights per checkpointed chunk")
+	reset := flags.Bool("reset", false, "forget chunk checkpoints and index the whole range again")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *to >= 0 && *to < *from {
+		fmt.Fprintf(os.Stderr, "backfill: --to %d must not be below --from %d\n", *to, *from)
+		flags.Usage()
+		return 2
+	}
+
+	model.InitDatabase()
+
+	if err := sync.Backfill(*from, *to, *workers, *chunk, *reset); err != nil {
+		log.Fatalf("Backfill error:%s", err.Error())
+		fmt.Fprintln(os.Stderr, "backfill:", err.Error())
+		return 1
+	}
+
+	return 0
+}
@@ -31,6 +31,12 @@ type appConfig struct {
 	RateInRedis      int64
 
 	Stats stats
+
+	Sync syncer `toml:"sync"`
+
+	Metrics metrics `toml:"metrics"`
+
+	Admin admin `toml:"admin"`
 }
 
 type database struct {
@@ -50,6 +56,31 @@ type stats struct {
 	ServerId string
 }
 
+type syncer struct {
+	Workers        int   //concurrent block fetchers
+	Prefetch       int64 //max blocks fetched ahead of the committed height
+	ReportInterval int64 //seconds between throughput reports
+
+	WebSocket           string //ws:// gateway for newHeads subscription, empty to poll only
+	ResubscribeInterval int64  //seconds of polling before subscribing again after the subscription drops
+	HeadTimeout         int64  //seconds without a newHeads notification before falling back to polling
+
+	Trace bool //trace the internal calls of contract transactions, the gateway needs the debug api
+}
+
+type metrics struct {
+	OnlineWindow     int64  //blocks, the miners of them are online
+	DifficultyWindow int64  //blocks, the network capacity is estimated from their average difficulty
+	SnapshotInterval int64  //blocks between the snapshots in t_summary_history
+	MortgagePerTB    string //wei a miner stakes per TB of capacity, empty disables the compliance apis
+
+	CapacityPerDifficulty int64 //bytes of plot per unit of difficulty, 0 for metrics.CAPACITYPERDIFFICULTY
+}
+
+type admin struct {
+	Token string //X-Admin-Token of the admin apis, empty disables them
+}
+
 //
 
 var (
@@ -75,6 +106,38 @@ func Config() *appConfig {
 			cfg.Stats.ServerId = "Scan&Stats"
 		}
 
+		if cfg.Sync.Workers <= 0 {
+			cfg.Sync.Workers = 8
+		}
+
+		if cfg.Sync.Prefetch <= 0 {
+			cfg.Sync.Prefetch = 64
+		}
+
+		if cfg.Sync.ReportInterval <= 0 {
+			cfg.Sync.ReportInterval = 60
+		}
+
+		if cfg.Sync.ResubscribeInterval <= 0 {
+			cfg.Sync.ResubscribeInterval = 30
+		}
+
+		if cfg.Sync.HeadTimeout <= 0 {
+			cfg.Sync.HeadTimeout = 600
+		}
+
+		if cfg.Metrics.OnlineWindow <= 0 {
+			cfg.Metrics.OnlineWindow = 480
+		}
+
+		if cfg.Metrics.DifficultyWindow <= 0 {
+			cfg.Metrics.DifficultyWindow = 100
+		}
+
+		if cfg.Metrics.SnapshotInterval <= 0 {
+			cfg.Metrics.SnapshotInterval = 20
+		}
+
 		log.Debugf("config:%+v\n", cfg)
 	})
 	return &cfg
@@ -22,12 +22,15 @@ const (
 	ERR_INNER_ERROR = -99999
 
 	ERR_PARAMETER_INVALID = 10000
+	ERR_PERMISSION_DENIED = 10001
 
 	ERR_RPC_ERROR = 20000
 
 	BLOCK_COUNT_ERROR        = 30000
 	GET_BLOCKS_ERROR         = 30001
 	BLOCK_OR_TRANS_NOT_EXIST = 30002
+	CONTRACT_NOT_EXIST       = 30003
+	NFT_NOT_EXIST            = 30004
 	REPEAT_TRANSACTION       = 30400
 
 	TRANSACTION_COUNT_ERROR = 40000
@@ -47,6 +50,30 @@ const (
 	MORTGAGECONTRACT_FUNC_MORTGAGE = "0x43794dda"
 	MORTGAGECONTRACT_FUNC_REDEEM   = "0x1e9a6950"
 	ONEDAYBLOCK                    = 480
+	TARGETBLOCKTIME                = 86400 / ONEDAYBLOCK //目标出块时间(秒)
+	SCOOPNUMBER                    = 4096                //每个nonce的scoop数
+	SCOOPSIZE                      = 64                  //每个scoop的字节数，两个32字节哈希
+	MAXREORGDEPTH                  = 1000                //链重组最多回滚的区块数
+	STATSHEADLAG                   = 10                  //离链头不超过这么多区块时，每写入一个区块都重新统计当天的地址
+	ZEROADDR                       = "0x0000000000000000000000000000000000000000"
+)
+
+//erc20
+const (
+	TOKEN_TYPE_ERC20       = "erc20"
+	TOKEN_TRANSFER_TOPIC   = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" //Transfer(address,address,uint256)
+	TOKEN_FUNC_NAME        = "0x06fdde03"
+	TOKEN_FUNC_SYMBOL      = "0x95d89b41"
+	TOKEN_FUNC_DECIMALS    = "0x313ce567"
+	TOKEN_FUNC_TOTALSUPPLY = "0x18160ddd"
+	MAXTOKENBALANCEDIGITS  = 60 //t_token_balance是decimal(65,0)，更大的转账不计入余额
+)
+
+//erc721
+const (
+	TOKEN_TYPE_ERC721 = "erc721"
+	ERC721_ABI        = `[{"constant":true,"inputs":[{"name":"tokenId","type":"uint256"}],"name":"tokenURI",` +
+		`"outputs":[{"name":"","type":"string"}],"payable":false,"stateMutability":"view","type":"function"}]`
 )
 
 //
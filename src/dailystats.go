@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/EthereumHD/Scan/src/sync"
+	"qoobing.com/utillib.golang/log"
+)
+
+// dailyStats is `scan daily-stats --from 2006-01-02 --to 2006-01-02`, it rebuilds t_daily_stats of the UTC days
+func dailyStats(args []string) int {
+	flags := flag.NewFlagSet("daily-stats", flag.ContinueOnError)
+	fromDate := flags.String("from", "", "first UTC day, default the day of block 1")
+	toDate := flags.String("to", "", "last UTC day, default today")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	withDate := "2006-01-02"
+	to := time.Now().UTC()
+	if *toDate != "" {
+		t, err := time.ParseInLocation(withDate, *toDate, time.UTC)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "daily-stats: --to %s is not a date\n", *toDate)
+			return 2
+		}
+		to = t
+	}
+
+	from := int64(-1)
+	if *fromDate != "" {
+		t, err := time.ParseInLocation(withDate, *fromDate, time.UTC)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "daily-stats: --from %s is not a date\n", *fromDate)
+			return 2
+		}
+		if to.Before(t) {
+			fmt.Fprintf(os.Stderr, "daily-stats: --to %s must not be before --from %s\n", to.Format(withDate), *fromDate)
+			flags.Usage()
+			return 2
+		}
+		from = t.Unix()
+	}
+
+	model.InitDatabase()
+
+	if err := sync.RebuildDailyStats(from, to.Unix()); err != nil {
+		log.Fatalf("RebuildDailyStats error:%s", err.Error())
+		fmt.Fprintln(os.Stderr, "daily-stats:", err.Error())
+		return 1
+	}
+
+	return 0
+}
@@ -27,6 +27,16 @@ import (
 
 func main() {
 
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		os.Exit(backfill(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "verify-rewards" {
+		os.Exit(verifyRewards(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "daily-stats" {
+		os.Exit(dailyStats(os.Args[2:]))
+	}
+
 	model.InitDatabase()
 
 	filePath, _ := exec.LookPath(os.Args[0])
@@ -35,7 +45,6 @@ func main() {
 	go stats.Start()
 	go sync.StartSyncLastBlock()
 	//go sync.StartSyncRate()
-	//go sync.CheckReward()
 
 	e := echo.New()
 	e.Use(func(h echo.HandlerFunc) echo.HandlerFunc {
@@ -66,6 +75,7 @@ func main() {
 	e.POST("/block/get_by_height", api.GetBlockByHeight)
 	e.POST("/block/get_blocks", api.GetBlocks)
 	e.POST("/block/get_by_hash", api.GetBlockByHash)
+	e.POST("/block/get_reorgs", api.GetReorgs)
 
 	//transaction
 	e.POST("/transaction/get_by_hash", api.GetTransactionByHash)
@@ -76,17 +86,72 @@ func main() {
 	e.POST("/transaction/get_addr_pending", api.GetAddrPending)
 	e.POST("/transaction/get_hash_pending", api.GetHashPending)
 
+	//log
+	e.POST("/log/query", api.QueryLogs)
+
+	//contract
+	e.POST("/contract/get_info", api.GetContractInfo)
+
+	//token
+	e.POST("/token/get_list", api.GetTokenList)
+	e.POST("/token/get_transfers", api.GetTokenTransfers)
+	e.POST("/token/get_holders", api.GetTokenHolders)
+	e.POST("/token/get_balances_by_addr", api.GetTokenBalancesByAddr)
+
+	//nft
+	e.POST("/nft/get_by_owner", api.GetNftByOwner)
+	e.POST("/nft/get_token", api.GetNftToken)
+	e.POST("/nft/get_transfers", api.GetNftTransfers)
+
+	//mortgage
+	e.POST("/mortgage/get_stake", api.GetStake)
+	e.POST("/mortgage/get_history", api.GetStakeHistory)
+	e.POST("/mortgage/get_top_stakers", api.GetTopStakers)
+	e.POST("/mortgage/get_total", api.GetTotalMortgage)
+	e.POST("/mortgage/get_compliance", api.GetCompliance)
+	e.POST("/mortgage/get_under_collateralised", api.GetUnderCollateralised)
+
 	//mining
 	e.POST("/mining/get_mined_block_by_addr", api.GetMinedBlocks)
 	e.POST("/mining/get_addr_mining_rewards", api.GetAddrMiningRewards)
 	e.POST("/mining/get_mined_block_by_addr_and_date", api.GetMinedblockByAddrAndDate)
+	e.POST("/mining/get_miner_performance", api.GetMinerPerformance)
+	e.POST("/mining/get_top_miners", api.GetTopMiners)
 
 	//poc
 	e.POST("/poc/get_exchange_rate", api.GetExchangeRate)
 	e.GET("/poc/get_exchange_rate", api.GetExchangeRate)
 	e.POST("/poc/get_summary", api.GetSummary)
 	e.GET("/poc/get_summary", api.GetSummary)
+	e.POST("/poc/get_summary_history", api.GetSummaryHistory)
+	e.GET("/poc/get_summary_history", api.GetSummaryHistory)
 	e.POST("/poc/get_balance", api.GetBalance)
+	e.POST("/poc/get_deadlines_by_miner", api.GetDeadlinesByMiner)
+	e.POST("/poc/get_deadline_history", api.GetDeadlineHistory)
+	e.POST("/poc/get_deadline_summary", api.GetDeadlineSummary)
+	e.POST("/poc/get_scoop_distribution", api.GetScoopDistribution)
+
+	//chart
+	e.POST("/chart/tx_count", api.GetChartTxCount)
+	e.POST("/chart/block_time", api.GetChartBlockTime)
+	e.POST("/chart/difficulty", api.GetChartDifficulty)
+	e.POST("/chart/active_addresses", api.GetChartActiveAddresses)
+	e.POST("/chart/fees", api.GetChartFees)
+
+	//label
+	e.POST("/label/get", api.GetLabel)
+	e.POST("/label/get_labels", api.GetLabels)
+	e.POST("/admin/label/set", api.SetLabel)
+	e.POST("/admin/label/delete", api.DeleteLabel)
+	e.POST("/admin/label/import", api.ImportLabels)
+
+	//search
+	e.GET("/search", api.Search)
+	e.POST("/search", api.Search)
+
+	//sync
+	e.POST("/sync/status", api.GetSyncStatus)
+	e.GET("/sync/status", api.GetSyncStatus)
 
 	e.Logger.Fatal(e.Start(":" + config.Config().Port))
 
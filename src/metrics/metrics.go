@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"errors"
+	"github.com/EthereumHD/Scan/src/config"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+	"sync"
+)
+
+// CAPACITYPERDIFFICULTY bytes of plot per unit of difficulty derived from the PoC parameters.
+// A round reads one scoop of each nonce and the deadline is hit/baseTarget, with hit uniform in
+// [0, 2^64). The best deadline of N nonces is then expected at 2^64/(N*baseTarget) = difficulty/N,
+// and the chain keeps it at TARGETBLOCKTIME, so N = difficulty/TARGETBLOCKTIME nonces of
+// SCOOPNUMBER*SCOOPSIZE bytes each: 4096*64/180 = 1456, the factor get_summary has always used.
+// metrics.CapacityPerDifficulty of the config overrides it
+const CAPACITYPERDIFFICULTY = SCOOPNUMBER * SCOOPSIZE / TARGETBLOCKTIME
+
+// TB bytes
+const TB = 1 << 40
+
+// PB bytes
+var PB = big.NewInt(0).Lsh(big.NewInt(1), 50)
+
+// Summary is the network figures at a block, computed from the indexed blocks up to it
+type Summary struct {
+	Height        int64
+	Hash          string
+	Timestamp     int64
+	Difficulty    *big.Int //average over DifficultyWindow blocks
+	Capacity      *big.Int //bytes
+	OnlineMiners  int64    //miners of the last OnlineWindow blocks
+	BlockCount24H int64
+	DayReward     *big.Int //reward of the blocks of the last 24 hours(wei)
+	PbDayReward   *big.Int //DayReward per PB of Capacity(wei)
+	TotalRewarded *big.Int //only in the snapshots, nil if not known
+	TotalMortgage *big.Int //only in the snapshots, nil if not known
+}
+
+var cache = struct {
+	sync.Mutex
+	summary *Summary
+}{}
+
+// Get the summary at the last indexed block, computed once per block. The snapshot of the block
+// in t_summary_history is used first, so the instances sharing the database compute it once
+func Get(db *gorm.DB) (Summary, error) {
+	blocks, err := model.GetRecentBlocks(db, 0, 1)
+	if err != nil {
+		return Summary{}, err
+	}
+	if len(blocks) == 0 {
+		return Summary{}, errors.New(DATA_NOT_EXIST)
+	}
+
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.summary != nil && cache.summary.Hash == blocks[0].F_hash {
+		return *cache.summary, nil
+	}
+
+	history, err := (&model.SummaryHistory{}).FindSummaryHistoryByHash(db, blocks[0].F_hash)
+	if err == nil {
+		summary := FromHistory(history)
+		cache.summary = &summary
+		return summary, nil
+	}
+
+	summary, err := Compute(db, blocks[0])
+	if err != nil {
+		return Summary{}, err
+	}
+	cache.summary = &summary
+
+	return summary, nil
+}
+
+// Compute the summary at block
+func Compute(db *gorm.DB, block model.Block) (summary Summary, err error) {
+	summary = Summary{
+		Height:    block.F_block,
+		Hash:      block.F_hash,
+		Timestamp: block.F_timestamp,
+	}
+
+	//1.average difficulty and capacity
+	recent, err := model.GetBlocksUpTo(db, block.F_block, int(config.Config().Metrics.DifficultyWindow))
+	if err != nil {
+		log.Debugf("GetBlocksUpTo:%d error:%s", block.F_block, err.Error())
+		return summary, err
+	}
+	summary.Difficulty = averageDifficulty(recent)
+	summary.Capacity = big.NewInt(0).Mul(summary.Difficulty, big.NewInt(CapacityPerDifficulty()))
+
+	//2.online miners
+	from := block.F_block - config.Config().Metrics.OnlineWindow + 1
+	summary.OnlineMiners, err = model.GetMinerNum(db, from, block.F_block)
+	if err != nil {
+		log.Debugf("GetMinerNum:[%d,%d] error:%s", from, block.F_block, err.Error())
+		return summary, err
+	}
+
+	//3.reward of the last day, and per PB of the capacity
+	day, err := model.GetBlocksInTime(db, block.F_timestamp-86400+1, block.F_timestamp)
+	if err != nil {
+		log.Debugf("GetBlocksInTime:%d error:%s", block.F_timestamp, err.Error())
+		return summary, err
+	}
+	summary.BlockCount24H = int64(len(day))
+	summary.DayReward = big.NewInt(0)
+	for _, b := range day {
+		reward, ok := big.NewInt(0).SetString(b.F_reward, 10)
+		if !ok {
+			log.Debugf("invalid reward:%s of block:%d", b.F_reward, b.F_block)
+			continue
+		}
+		summary.DayReward.Add(summary.DayReward, reward)
+	}
+	summary.PbDayReward = PbReward(summary.DayReward, summary.Capacity)
+
+	return summary, nil
+}
+
+// ToHistory the snapshot row of the summary
+func (s Summary) ToHistory() model.SummaryHistory {
+	history := model.SummaryHistory{
+		F_block:         s.Height,
+		F_block_hash:    s.Hash,
+		F_timestamp:     s.Timestamp,
+		F_difficulty:    s.Difficulty.String(),
+		F_capacity:      s.Capacity.String(),
+		F_online_miners: s.OnlineMiners,
+		F_block_count:   s.BlockCount24H,
+		F_day_reward:    s.DayReward.String(),
+		F_pb_day_reward: s.PbDayReward.String(),
+	}
+	if s.TotalRewarded != nil {
+		history.F_total_rewarded = s.TotalRewarded.String()
+	}
+	if s.TotalMortgage != nil {
+		history.F_total_mortgage = s.TotalMortgage.String()
+	}
+	return history
+}
+
+func FromHistory(h model.SummaryHistory) Summary {
+	return Summary{
+		Height:        h.F_block,
+		Hash:          h.F_block_hash,
+		Timestamp:     h.F_timestamp,
+		Difficulty:    parseBig(h.F_difficulty),
+		Capacity:      parseBig(h.F_capacity),
+		OnlineMiners:  h.F_online_miners,
+		BlockCount24H: h.F_block_count,
+		DayReward:     parseBig(h.F_day_reward),
+		PbDayReward:   parseBig(h.F_pb_day_reward),
+		TotalRewarded: parseBigOrNil(h.F_total_rewarded),
+		TotalMortgage: parseBigOrNil(h.F_total_mortgage),
+	}
+}
+
+func parseBig(s string) *big.Int {
+	if n := parseBigOrNil(s); n != nil {
+		return n
+	}
+	return big.NewInt(0)
+}
+
+func parseBigOrNil(s string) *big.Int {
+	n, ok := big.NewInt(0).SetString(s, 10)
+	if !ok {
+		return nil
+	}
+	return n
+}
+
+// CapacityPerDifficulty the configured bytes of plot per unit of difficulty, CAPACITYPERDIFFICULTY if not set
+func CapacityPerDifficulty() int64 {
+	if factor := config.Config().Metrics.CapacityPerDifficulty; factor > 0 {
+		return factor
+	}
+	return CAPACITYPERDIFFICULTY
+}
+
+// CapacityOf the network capacity in bytes estimated from a difficulty
+func CapacityOf(difficulty float64) float64 {
+	return difficulty * float64(CapacityPerDifficulty())
+}
+
+// PbReward reward per PB of capacity bytes, 0 when the capacity is unknown
+func PbReward(reward *big.Int, capacity *big.Int) *big.Int {
+	if capacity.Sign() <= 0 {
+		return big.NewInt(0)
+	}
+	pbReward := big.NewInt(0).Mul(reward, PB)
+	return pbReward.Div(pbReward, capacity)
+}
+
+// averageDifficulty of the blocks indexed with their detail
+func averageDifficulty(blocks []model.Block) *big.Int {
+	total, n := big.NewInt(0), int64(0)
+	for _, b := range blocks {
+		difficulty, ok := big.NewInt(0).SetString(b.F_difficulty, 10)
+		if !ok {
+			continue
+		}
+		total.Add(total, difficulty)
+		n++
+	}
+	if n == 0 {
+		return total
+	}
+	return total.Div(total, big.NewInt(n))
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/EthereumHD/Scan/src/config"
+	. "github.com/EthereumHD/Scan/src/const"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 	"qoobing.com/utillib.golang/log"
@@ -22,6 +23,14 @@ var Table = map[string]string{
 		"`F_status` int(4)  NOT NULL DEFAULT 0," +
 		"`F_tx_type` bigint(20)  NOT NULL DEFAULT 0," +
 		"`F_tx_type_ext` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_nonce` bigint(20) NOT NULL DEFAULT -1," +
+		"`F_tx_index` int(64) NOT NULL DEFAULT -1," +
+		"`F_gas` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_gas_price` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_gas_used` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_input` mediumtext NOT NULL," +
+		"`F_receipt_status` int(4) NOT NULL DEFAULT -1," +
+		"`F_contract_address` varchar(128) NOT NULL DEFAULT ''," +
 		"`F_create_time` datetime NOT NULL," +
 		"`F_modify_time` datetime NOT NULL," +
 
@@ -61,6 +70,11 @@ var Table = map[string]string{
 		"`F_parent_hash` varchar(128) NOT NULL DEFAULT ''," +
 		"`F_reward` varchar(128) NOT NULL DEFAULT ''," +
 		"`F_fees` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_difficulty` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_total_difficulty` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_size` bigint(20) NOT NULL DEFAULT -1," +
+		"`F_nonce` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_extra_data` text NOT NULL," +
 		"`F_status` int(4)  NOT NULL DEFAULT 0," +
 		"`F_create_time` datetime NOT NULL," +
 		"`F_modify_time` datetime NOT NULL," +
@@ -68,7 +82,8 @@ var Table = map[string]string{
 		"PRIMARY KEY (`F_id`)," +
 		"UNIQUE KEY (`F_hash`)," +
 		"INDEX (`F_miner`)," +
-		"INDEX (`F_block`)" +
+		"INDEX (`F_block`)," +
+		"INDEX (`F_timestamp`)" +
 		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
 
 	"t_miner_reward": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_miner_reward (" +
@@ -96,6 +111,424 @@ var Table = map[string]string{
 		"PRIMARY KEY (`F_id`)," +
 		"UNIQUE KEY (`F_timestamp`)" +
 		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_sync_state": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_sync_state (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_name` varchar(64) NOT NULL DEFAULT ''," +
+		"`F_height` int(64)  NOT NULL DEFAULT -1," +
+		"`F_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_head` int(64)  NOT NULL DEFAULT -1," +
+		"`F_head_time` int(64)  NOT NULL DEFAULT 0," +
+		"`F_reorg_count` bigint(20)  NOT NULL DEFAULT 0," +
+		"`F_last_reorg_time` int(64)  NOT NULL DEFAULT 0," +
+		"`F_error_count` bigint(20)  NOT NULL DEFAULT 0," +
+		"`F_last_error` varchar(512) NOT NULL DEFAULT ''," +
+		"`F_last_error_time` int(64)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_name`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_reorg": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_reorg (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_ancestor` int(64)  NOT NULL DEFAULT -1," +
+		"`F_ancestor_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_depth` int(64)  NOT NULL DEFAULT 0," +
+		"`F_old_head` int(64)  NOT NULL DEFAULT -1," +
+		"`F_old_head_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_new_head` int(64)  NOT NULL DEFAULT -1," +
+		"`F_new_head_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_orphaned_hashes` text NOT NULL," +
+		"`F_timestamp` int(64)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"INDEX (`F_ancestor`)," +
+		"INDEX (`F_timestamp`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_log": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_log (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_timestamp` int(64)   NOT NULL DEFAULT -1," +
+		"`F_tx_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_tx_index` int(64)  NOT NULL DEFAULT -1," +
+		"`F_log_index` int(64)  NOT NULL DEFAULT -1," +
+		"`F_address` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_topic0` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_topic1` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_topic2` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_topic3` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_data` mediumtext NOT NULL," +
+		"`F_status` int(4)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_block_hash`, `F_log_index`)," +
+		"INDEX (`F_block`)," +
+		"INDEX (`F_tx_hash`)," +
+		"INDEX (`F_address`, `F_block`)," +
+		"INDEX (`F_topic0`, `F_block`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_contract": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_contract (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_address` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_creator` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_tx_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_timestamp` int(64)   NOT NULL DEFAULT -1," +
+		"`F_code_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_code_size` int(64)  NOT NULL DEFAULT 0," +
+		"`F_status` int(4)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_address`)," +
+		"INDEX (`F_creator`)," +
+		"INDEX (`F_block`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_token": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_token (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_address` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_type` varchar(16) NOT NULL DEFAULT ''," +
+		"`F_name` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_symbol` varchar(64) NOT NULL DEFAULT ''," +
+		"`F_decimals` int(4)  NOT NULL DEFAULT -1," +
+		"`F_total_supply` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_supply_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_address`)," +
+		"INDEX (`F_symbol`)," +
+		"INDEX (`F_name`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_token_transfer": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_token_transfer (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_timestamp` int(64)   NOT NULL DEFAULT -1," +
+		"`F_tx_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_log_index` int(64)  NOT NULL DEFAULT -1," +
+		"`F_token` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_from` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_to` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_value` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_status` int(4)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_block_hash`, `F_log_index`)," +
+		"INDEX (`F_block`)," +
+		"INDEX (`F_tx_hash`)," +
+		"INDEX (`F_token`, `F_block`)," +
+		"INDEX (`F_from`, `F_block`)," +
+		"INDEX (`F_to`, `F_block`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_token_balance": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_token_balance (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_token` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_holder` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_balance` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_token`, `F_holder`)," +
+		"INDEX (`F_holder`)," +
+		"INDEX (`F_token`, `F_balance`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_nft_transfer": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_nft_transfer (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_timestamp` int(64)   NOT NULL DEFAULT -1," +
+		"`F_tx_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_log_index` int(64)  NOT NULL DEFAULT -1," +
+		"`F_token` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_token_id` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_from` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_to` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_status` int(4)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_block_hash`, `F_log_index`)," +
+		"INDEX (`F_block`)," +
+		"INDEX (`F_token`, `F_token_id`, `F_block`)," +
+		"INDEX (`F_from`, `F_block`)," +
+		"INDEX (`F_to`, `F_block`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_nft_owner": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_nft_owner (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_token` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_token_id` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_owner` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_log_index` int(64)  NOT NULL DEFAULT -1," +
+		"`F_token_uri` varchar(2048) NOT NULL DEFAULT ''," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_token`, `F_token_id`)," +
+		"INDEX (`F_owner`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_internal_tx": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_internal_tx (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_timestamp` int(64)   NOT NULL DEFAULT -1," +
+		"`F_tx_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_trace_index` int(64)  NOT NULL DEFAULT -1," +
+		"`F_depth` int(8)  NOT NULL DEFAULT 0," +
+		"`F_type` varchar(32) NOT NULL DEFAULT ''," +
+		"`F_from` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_to` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_value` varchar(128) NOT NULL DEFAULT '0'," +
+		"`F_error` varchar(512) NOT NULL DEFAULT ''," +
+		"`F_status` int(4)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_block_hash`, `F_trace_index`)," +
+		"INDEX (`F_block`)," +
+		"INDEX (`F_tx_hash`)," +
+		"INDEX (`F_from`, `F_block`)," +
+		"INDEX (`F_to`, `F_block`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_trace_missing": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_trace_missing (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_tx_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_error` varchar(512) NOT NULL DEFAULT ''," +
+		"`F_status` int(4)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_block_hash`, `F_tx_hash`)," +
+		"INDEX (`F_block`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_mortgage": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_mortgage (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_tx_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_timestamp` int(64)   NOT NULL DEFAULT -1," +
+		"`F_tx_index` int(64)  NOT NULL DEFAULT -1," +
+		"`F_from` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_addr` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_type` int(4)  NOT NULL DEFAULT 0," +
+		"`F_amount` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_value` varchar(128) NOT NULL DEFAULT '0'," +
+		"`F_success` int(4)  NOT NULL DEFAULT 0," +
+		"`F_status` int(4)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_tx_hash`)," +
+		"INDEX (`F_block`)," +
+		"INDEX (`F_addr`, `F_block`)," +
+		"INDEX (`F_from`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_block_poc": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_block_poc (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_miner` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_timestamp` int(64)   NOT NULL DEFAULT -1," +
+		"`F_deadline` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_nonce` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_scoop` int(64)  NOT NULL DEFAULT -1," +
+		"`F_status` int(4)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_block_hash`)," +
+		"INDEX (`F_block`)," +
+		"INDEX (`F_timestamp`)," +
+		"INDEX (`F_miner`, `F_timestamp`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_summary_history": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_summary_history (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_timestamp` int(64)   NOT NULL DEFAULT -1," +
+		"`F_difficulty` varchar(128) NOT NULL DEFAULT '0'," +
+		"`F_capacity` varchar(128) NOT NULL DEFAULT '0'," +
+		"`F_online_miners` int(64)  NOT NULL DEFAULT 0," +
+		"`F_block_count` int(64)  NOT NULL DEFAULT 0," +
+		"`F_day_reward` varchar(128) NOT NULL DEFAULT '0'," +
+		"`F_pb_day_reward` varchar(128) NOT NULL DEFAULT '0'," +
+		"`F_total_rewarded` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_total_mortgage` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_status` int(4)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_block_hash`)," +
+		"INDEX (`F_block`)," +
+		"INDEX (`F_timestamp`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_reward_ledger": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_reward_ledger (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_block_hash` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_seq` int(64)  NOT NULL DEFAULT 0," +
+		"`F_miner` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_timestamp` int(64)   NOT NULL DEFAULT -1," +
+		"`F_sign` int(4)  NOT NULL DEFAULT 0," +
+		"`F_reward` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_fees` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_block_hash`, `F_seq`)," +
+		"INDEX (`F_block`)," +
+		"INDEX (`F_miner`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_daily_stats": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_daily_stats (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_day` int(64)   NOT NULL DEFAULT -1," +
+		"`F_first_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_last_block` int(64)  NOT NULL DEFAULT -1," +
+		"`F_first_timestamp` int(64)  NOT NULL DEFAULT 0," +
+		"`F_last_timestamp` int(64)  NOT NULL DEFAULT 0," +
+		"`F_blocks` int(64)  NOT NULL DEFAULT 0," +
+		"`F_txs` int(64)  NOT NULL DEFAULT 0," +
+		"`F_fees` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_reward` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_difficulty_sum` double NOT NULL DEFAULT 0," +
+		"`F_difficulty_blocks` int(64)  NOT NULL DEFAULT 0," +
+		"`F_gas_used` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_gas_limit` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_avg_block_time` double NOT NULL DEFAULT 0," +
+		"`F_avg_difficulty` double NOT NULL DEFAULT 0," +
+		"`F_active_addresses` int(64)  NOT NULL DEFAULT 0," +
+		"`F_new_addresses` int(64)  NOT NULL DEFAULT 0," +
+		"`F_gas_used_ratio` double NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_day`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_miner_hourly": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_miner_hourly (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_hour` int(64)   NOT NULL DEFAULT -1," +
+		"`F_miner` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_blocks` int(64)  NOT NULL DEFAULT 0," +
+		"`F_reward` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_fees` decimal(65,0) NOT NULL DEFAULT 0," +
+		"`F_difficulty_sum` double NOT NULL DEFAULT 0," +
+		"`F_difficulty_blocks` int(64)  NOT NULL DEFAULT 0," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_hour`, `F_miner`)," +
+		"INDEX (`F_miner`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+
+	"t_address_label": "CREATE TABLE IF NOT EXISTS " + Schema + ".t_address_label (" +
+		"`F_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+		"`F_addr` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_label` varchar(128) NOT NULL DEFAULT ''," +
+		"`F_category` varchar(64) NOT NULL DEFAULT ''," +
+		"`F_source` varchar(64) NOT NULL DEFAULT ''," +
+		"`F_create_time` datetime NOT NULL," +
+		"`F_modify_time` datetime NOT NULL," +
+
+		"PRIMARY KEY (`F_id`)," +
+		"UNIQUE KEY (`F_addr`)," +
+		"INDEX (`F_label`)," +
+		"INDEX (`F_category`)" +
+		") ENGINE=InnoDB  DEFAULT CHARSET=utf8 ;",
+}
+
+//Migration upgrade tables created by older versions, run in order after Table.
+//A statement already applied fails and is ignored like the CREATE TABLE IF NOT EXISTS above
+var Migration = []string{
+	"ALTER TABLE " + Schema + ".t_transaction ADD COLUMN `F_nonce` bigint(20) NOT NULL DEFAULT -1",
+	"ALTER TABLE " + Schema + ".t_transaction ADD COLUMN `F_tx_index` int(64) NOT NULL DEFAULT -1",
+	"ALTER TABLE " + Schema + ".t_transaction ADD COLUMN `F_gas` varchar(128) NOT NULL DEFAULT ''",
+	"ALTER TABLE " + Schema + ".t_transaction ADD COLUMN `F_gas_price` varchar(128) NOT NULL DEFAULT ''",
+	"ALTER TABLE " + Schema + ".t_transaction ADD COLUMN `F_gas_used` varchar(128) NOT NULL DEFAULT ''",
+	"ALTER TABLE " + Schema + ".t_transaction ADD COLUMN `F_input` mediumtext NOT NULL",
+	"ALTER TABLE " + Schema + ".t_transaction ADD COLUMN `F_receipt_status` int(4) NOT NULL DEFAULT -1",
+	"ALTER TABLE " + Schema + ".t_transaction ADD COLUMN `F_contract_address` varchar(128) NOT NULL DEFAULT ''",
+
+	"ALTER TABLE " + Schema + ".t_block ADD COLUMN `F_difficulty` varchar(128) NOT NULL DEFAULT ''",
+	"ALTER TABLE " + Schema + ".t_block ADD COLUMN `F_total_difficulty` varchar(128) NOT NULL DEFAULT ''",
+	"ALTER TABLE " + Schema + ".t_block ADD COLUMN `F_size` bigint(20) NOT NULL DEFAULT -1",
+	"ALTER TABLE " + Schema + ".t_block ADD COLUMN `F_nonce` varchar(128) NOT NULL DEFAULT ''",
+	"ALTER TABLE " + Schema + ".t_block ADD COLUMN `F_extra_data` text NOT NULL",
+	"ALTER TABLE " + Schema + ".t_block ADD INDEX `F_timestamp` (`F_timestamp`)",
+	"ALTER TABLE " + Schema + ".t_token ADD COLUMN `F_supply_block` int(64) NOT NULL DEFAULT -1",
+	"ALTER TABLE " + Schema + ".t_token ADD INDEX `F_symbol` (`F_symbol`)",
+	"ALTER TABLE " + Schema + ".t_token ADD INDEX `F_name` (`F_name`)",
+	"ALTER TABLE " + Schema + ".t_daily_stats ADD COLUMN `F_first_timestamp` int(64) NOT NULL DEFAULT 0",
+	"ALTER TABLE " + Schema + ".t_daily_stats ADD COLUMN `F_last_timestamp` int(64) NOT NULL DEFAULT 0",
+	"ALTER TABLE " + Schema + ".t_daily_stats ADD COLUMN `F_difficulty_sum` double NOT NULL DEFAULT 0",
+	"ALTER TABLE " + Schema + ".t_daily_stats ADD COLUMN `F_difficulty_blocks` int(64) NOT NULL DEFAULT 0",
+	"ALTER TABLE " + Schema + ".t_daily_stats ADD COLUMN `F_gas_used` decimal(65,0) NOT NULL DEFAULT 0",
+	"ALTER TABLE " + Schema + ".t_daily_stats ADD COLUMN `F_gas_limit` decimal(65,0) NOT NULL DEFAULT 0",
+
+	//seed labels, kept if an admin changed them
+	"INSERT IGNORE INTO " + Schema + ".t_address_label (F_addr, F_label, F_category, F_source, F_create_time, F_modify_time) " +
+		"VALUES ('" + MORTGAGECONTRACTADDR + "', 'Mortgage Contract', 'contract', 'system', NOW(), NOW())",
+}
+
+//InTransaction run fn in one database transaction, it is rolled back when fn fails or panics
+func InTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) (err error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
 func InitDatabase() {
@@ -109,4 +542,8 @@ func InitDatabase() {
 	for _, value := range Table {
 		db.Exec(value)
 	}
+
+	for _, value := range Migration {
+		db.Exec(value)
+	}
 }
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// 地址标签，交易所热钱包、矿池、系统合约等
+type AddressLabel struct {
+	F_id          uint64 `gorm:"column:F_id"`   //ID
+	F_addr        string `gorm:"column:F_addr"` //小写
+	F_label       string `gorm:"column:F_label"`
+	F_category    string `gorm:"column:F_category"`    //exchange，pool，contract，treasury等
+	F_source      string `gorm:"column:F_source"`      //来源，admin，import，system等
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+func (l *AddressLabel) TableName() string {
+	return "t_address_label"
+}
+
+// SaveAddressLabels insert the labels, or replace the label, category and source of the addresses labeled before
+func SaveAddressLabels(db *gorm.DB, labels []AddressLabel) (err error) {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	values := make([]string, 0, len(labels))
+	args := make([]interface{}, 0, len(labels)*6)
+	for _, l := range labels {
+		ASSERT(l.F_addr != "", "SaveAddressLabels, F_addr can't be nul")
+
+		values = append(values, "(?,?,?,?,?,?)")
+		args = append(args, strings.ToLower(l.F_addr), l.F_label, l.F_category, l.F_source, newFormat, newFormat)
+	}
+
+	sql := "INSERT INTO t_address_label (F_addr, F_label, F_category, F_source, F_create_time, F_modify_time) VALUES " +
+		strings.Join(values, ",") + " ON DUPLICATE KEY UPDATE F_label = VALUES(F_label), F_category = VALUES(F_category), " +
+		"F_source = VALUES(F_source), F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, args...)
+	if rdb.Error != nil {
+		log.Debugf("SaveAddressLabels error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// DeleteAddressLabel remove the label of addr, DATA_NOT_EXIST if it has none
+func DeleteAddressLabel(db *gorm.DB, addr string) (err error) {
+	rdb := db.Where("F_addr = ?", strings.ToLower(addr)).Delete(AddressLabel{})
+	if rdb.Error != nil {
+		return errors.New("DeleteAddressLabel error:" + rdb.Error.Error())
+	}
+	if rdb.RowsAffected == 0 {
+		return errors.New(DATA_NOT_EXIST)
+	}
+
+	return nil
+}
+
+func (l *AddressLabel) FindAddressLabel(db *gorm.DB, addr string) (label AddressLabel, err error) {
+
+	rdb := db.Where("F_addr = ?", strings.ToLower(addr)).First(&label)
+	if rdb.RecordNotFound() {
+		err = errors.New(DATA_NOT_EXIST)
+	} else if rdb.Error != nil {
+		panic("FindAddressLabel error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return label, err
+}
+
+// GetAddressLabels the labels of the addresses which have one, keyed by the lowercase address
+func GetAddressLabels(db *gorm.DB, addrs []string) (labels map[string]AddressLabel, err error) {
+	labels = make(map[string]AddressLabel)
+
+	keys := make([]string, 0, len(addrs))
+	seen := make(map[string]bool)
+	for _, addr := range addrs {
+		addr = strings.ToLower(addr)
+		if addr != "" && !seen[addr] {
+			seen[addr] = true
+			keys = append(keys, addr)
+		}
+	}
+	if len(keys) == 0 {
+		return labels, nil
+	}
+
+	list := make([]AddressLabel, 0)
+	rdb := db.Where("F_addr in (?)", keys).Find(&list)
+	if rdb.Error != nil {
+		return labels, errors.New("GetAddressLabels error:" + rdb.Error.Error())
+	}
+	for _, label := range list {
+		labels[label.F_addr] = label
+	}
+
+	return labels, nil
+}
+
+// GetAddressLabelList the labels of a category, all if empty, in address order and their number
+func GetAddressLabelList(db *gorm.DB, category string, offset int, size int) (labels []AddressLabel, count int64, err error) {
+	rdb := db.Table("t_address_label")
+	if category != "" {
+		rdb = rdb.Where("F_category = ?", category)
+	}
+
+	num := Count_number{}
+	cdb := rdb.Select(" count(*) as count ").Find(&num)
+	if cdb.Error != nil {
+		err = errors.New("GetAddressLabelList error:" + cdb.Error.Error())
+		return
+	}
+
+	rdb = rdb.Select("*").Order("F_addr").Offset(offset).Limit(size).Find(&labels)
+	if rdb.Error != nil {
+		err = errors.New("GetAddressLabelList error:" + rdb.Error.Error())
+		return
+	}
+
+	return labels, num.Count, nil
+}
+
+// SearchAddressLabels the labels starting with prefix, shortest first so an exact match comes first
+func SearchAddressLabels(db *gorm.DB, prefix string, size int) (labels []AddressLabel, err error) {
+	rdb := db.Where("F_label LIKE ?", likePrefix(prefix)).Order("LENGTH(F_label), F_label").Limit(size).Find(&labels)
+	if rdb.Error != nil {
+		err = errors.New("SearchAddressLabels error:" + rdb.Error.Error())
+	}
+
+	return labels, err
+}
+
+// likePrefix the LIKE pattern of the strings starting with prefix
+func likePrefix(prefix string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(prefix) + "%"
+}
@@ -11,20 +11,25 @@ import (
 )
 
 type Block struct {
-	F_id          uint64 `gorm:"column:F_id"` //ID
-	F_block       int64  `gorm:"column:F_block"`
-	F_timestamp   int64  `gorm:"column:F_timestamp"`
-	F_txn         int64  `gorm:"column:F_txn"` //区块交易个数
-	F_miner       string `gorm:"column:F_miner"`
-	F_gas_used    string `gorm:"column:F_gas_used"`
-	F_gas_limit   string `gorm:"column:F_gas_limit"`
-	F_hash        string `gorm:"column:F_hash"`
-	F_parent_hash string `gorm:"column:F_parent_hash"`
-	F_reward      string `gorm:"column:F_reward"`      //区块奖励
-	F_fees        string `gorm:"column:F_fees"`        //区块手续费总和
-	F_status      int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
-	F_create_time string `gorm:"column:F_create_time"` //创建时间
-	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+	F_id               uint64 `gorm:"column:F_id"` //ID
+	F_block            int64  `gorm:"column:F_block"`
+	F_timestamp        int64  `gorm:"column:F_timestamp"`
+	F_txn              int64  `gorm:"column:F_txn"` //区块交易个数
+	F_miner            string `gorm:"column:F_miner"`
+	F_gas_used         string `gorm:"column:F_gas_used"`
+	F_gas_limit        string `gorm:"column:F_gas_limit"`
+	F_hash             string `gorm:"column:F_hash"`
+	F_parent_hash      string `gorm:"column:F_parent_hash"`
+	F_reward           string `gorm:"column:F_reward"` //区块奖励
+	F_fees             string `gorm:"column:F_fees"`   //区块手续费总和
+	F_difficulty       string `gorm:"column:F_difficulty"`
+	F_total_difficulty string `gorm:"column:F_total_difficulty"`
+	F_size             int64  `gorm:"column:F_size"`
+	F_nonce            string `gorm:"column:F_nonce"`
+	F_extra_data       string `gorm:"column:F_extra_data"`
+	F_status           int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
+	F_create_time      string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time      string `gorm:"column:F_modify_time"` //修改时间
 
 }
 
@@ -39,6 +44,20 @@ type MinedBlocksGroupByDate struct {
 	Fees   string `gorm:"column:fees"`
 }
 
+// MinerDailyBlocks is the blocks of the network and of one miner in a UTC day
+type MinerDailyBlocks struct {
+	Day        int64   `gorm:"column:day"` //当天0点，unix秒
+	Blocks     int64   `gorm:"column:blocks"`
+	Won        int64   `gorm:"column:won"`        //矿工爆块数
+	Difficulty float64 `gorm:"column:difficulty"` //平均难度，没有难度的区块不计入
+}
+
+// MinerWins is the NORMAL blocks mined by a miner in a time range
+type MinerWins struct {
+	F_miner string `gorm:"column:F_miner"`
+	Won     int64  `gorm:"column:won"`
+}
+
 func (b *Block) TableName() string {
 	return "t_block"
 }
@@ -123,20 +142,51 @@ func (b *Block) UpdateBlockStatus(db *gorm.DB) (err error) {
 	return b.updateBlockColumn(db, updateinfo)
 }
 
-func (b *Block) updateBlockColumn(db *gorm.DB, updateinfo map[string]interface{}) (err error) {
+//UpdateBlockDetail fill the detail columns of a block synced by an older version
+func (b *Block) UpdateBlockDetail(db *gorm.DB) (err error) {
+	updateinfo := map[string]interface{}{
+		"F_difficulty":       b.F_difficulty,
+		"F_total_difficulty": b.F_total_difficulty,
+		"F_size":             b.F_size,
+		"F_nonce":            b.F_nonce,
+		"F_extra_data":       b.F_extra_data,
+	}
+	return b.updateBlockColumn(db, updateinfo)
+}
 
-	log.Debugf("updateBlockColumn F_id:%d,hash:%s,%+v", b.F_id, b.F_hash, updateinfo)
+//ToBlockDetail the detail api output of an indexed block, without the poc fields
+func (b *Block) ToBlockDetail() BlockDetail {
+	return BlockDetail{
+		Height:         b.F_block,
+		Timestamp:      b.F_timestamp,
+		Transactions:   b.F_txn,
+		Hash:           b.F_hash,
+		ParentHash:     b.F_parent_hash,
+		Miner:          b.F_miner,
+		Difficult:      b.F_difficulty,
+		TotalDifficult: b.F_total_difficulty,
+		Size:           b.F_size,
+		GasUsed:        b.F_gas_used,
+		GasLimit:       b.F_gas_limit,
+		Nonce:          b.F_nonce,
+		BlockReward:    b.F_reward,
+		BlockFees:      b.F_fees,
+		ExtraData:      b.F_extra_data,
+	}
+}
+
+//HasDetail whether the row was written with the detail columns, rows synced by older versions have none
+func (b *Block) HasDetail() bool {
+	return b.F_difficulty != ""
+}
 
-	tx := db.Begin()
+func (b *Block) updateBlockColumn(db *gorm.DB, updateinfo map[string]interface{}) (err error) {
 
-	rdb := tx.Where("F_id = ?", b.F_id).Model(&b).Update(updateinfo)
+	log.Debugf("updateBlockColumn F_id:%d,hash:%s,%+v", b.F_id, b.F_hash, updateinfo)
 
-	if rdb.Error != nil {
-		tx.Rollback()
-		return rdb.Error
-	}
+	//single statement, db may be a transaction of the caller
+	rdb := db.Where("F_id = ?", b.F_id).Model(&b).Update(updateinfo)
 
-	tx.Commit()
 	return rdb.Error
 }
 
@@ -179,6 +229,72 @@ func GetRecentOneDayBlockNumber(db *gorm.DB) (n int64, err error) {
 	return num.Count, err
 }
 
+// GetBlocksUpTo the last size NORMAL blocks at or below height, newest first
+func GetBlocksUpTo(db *gorm.DB, height int64, size int) (blocks []Block, err error) {
+	rdb := db.Where("F_block <= ? and F_status = ?", height, NORMAL).Order("F_block desc").Limit(size).Find(&blocks)
+	if rdb.Error != nil {
+		err = errors.New("GetBlocksUpTo error:" + rdb.Error.Error())
+	}
+
+	return blocks, err
+}
+
+// GetBlocksByHeight the blocks of heights [from, to], forked ones included
+func GetBlocksByHeight(db *gorm.DB, from int64, to int64) (blocks []Block, err error) {
+	rdb := db.Where("F_block >= ? and F_block <= ?", from, to).Order("F_block").Find(&blocks)
+	if rdb.Error != nil {
+		err = errors.New("GetBlocksByHeight error:" + rdb.Error.Error())
+	}
+
+	return blocks, err
+}
+
+// GetBlocksInTime the NORMAL blocks mined in [start, end]
+func GetBlocksInTime(db *gorm.DB, start int64, end int64) (blocks []Block, err error) {
+	rdb := db.Where("F_timestamp >= ? and F_timestamp <= ? and F_status = ?", start, end, NORMAL).Find(&blocks)
+	if rdb.Error != nil {
+		err = errors.New("GetBlocksInTime error:" + rdb.Error.Error())
+	}
+
+	return blocks, err
+}
+
+// GetMinerNum the number of addresses that mined a NORMAL block of heights [from, to]
+func GetMinerNum(db *gorm.DB, from int64, to int64) (count int64, err error) {
+	num := Count_number{}
+	rdb := db.Table("t_block").Where("F_block >= ? and F_block <= ? and F_status = ?", from, to, NORMAL).
+		Select(" count(distinct F_miner) as count ").Find(&num)
+	if rdb.Error != nil {
+		err = errors.New("GetMinerNum error:" + rdb.Error.Error())
+	}
+
+	return num.Count, err
+}
+
+// GetMinerDailyBlocks the NORMAL blocks of each UTC day in [start, end), and the ones mined by addr
+func GetMinerDailyBlocks(db *gorm.DB, addr string, start int64, end int64) (days []MinerDailyBlocks, err error) {
+	rdb := db.Table("t_block").Where("F_timestamp >= ? and F_timestamp < ? and F_status = ?", start, end, NORMAL).
+		Select("F_timestamp DIV 86400 * 86400 as day, count(*) as blocks, CAST(SUM(F_miner = ?) AS SIGNED) as won, "+
+			"COALESCE(AVG(IF(F_difficulty = '', NULL, F_difficulty)), 0) as difficulty", addr).
+		Group("day").Order("day").Scan(&days)
+	if rdb.Error != nil {
+		err = errors.New("GetMinerDailyBlocks error:" + rdb.Error.Error())
+	}
+
+	return days, err
+}
+
+// GetMinerWins the blocks won by each miner in [start, end)
+func GetMinerWins(db *gorm.DB, start int64, end int64) (wins []MinerWins, err error) {
+	rdb := db.Table("t_block").Where("F_timestamp >= ? and F_timestamp < ? and F_status = ?", start, end, NORMAL).
+		Select("F_miner, count(*) as won").Group("F_miner").Scan(&wins)
+	if rdb.Error != nil {
+		err = errors.New("GetMinerWins error:" + rdb.Error.Error())
+	}
+
+	return wins, err
+}
+
 func GetBlocksByMinerAddr(db *gorm.DB, addr string, offset int, size int) (blocks []Block, err error) {
 	rdb := db.Where("F_miner = ? and F_status = ?", addr, NORMAL).Order("F_block desc").Offset(offset).Limit(size).Find(&blocks)
 	if rdb.Error != nil {
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"time"
+)
+
+// 区块的PoC证明，eth_getBlockPocByNumber
+type BlockPoc struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_block       int64  `gorm:"column:F_block"`
+	F_block_hash  string `gorm:"column:F_block_hash"`
+	F_miner       string `gorm:"column:F_miner"`
+	F_timestamp   int64  `gorm:"column:F_timestamp"`
+	F_deadline    string `gorm:"column:F_deadline"`    //decimal(65,0)
+	F_nonce       string `gorm:"column:F_nonce"`       //十进制
+	F_scoop       int64  `gorm:"column:F_scoop"`       //0 ~ SCOOPNUMBER-1
+	F_status      int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+// DeadlineStats is the deadline of a group of NORMAL blocks, by miner or by time
+type DeadlineStats struct {
+	F_miner        string  `gorm:"column:F_miner"`
+	Time           int64   `gorm:"column:period"` //时间段的起点
+	Blocks         int64   `gorm:"column:blocks"`
+	AvgDeadline    float64 `gorm:"column:avg_deadline"`
+	MinDeadline    string  `gorm:"column:min_deadline"`
+	MaxDeadline    string  `gorm:"column:max_deadline"`
+	FirstTimestamp int64   `gorm:"column:first_timestamp"`
+	LastTimestamp  int64   `gorm:"column:last_timestamp"`
+}
+
+// ScoopCount is the number of NORMAL blocks whose scoop is in [Scoop, Scoop+bucket size)
+type ScoopCount struct {
+	Scoop  int64 `gorm:"column:scoop"`
+	Blocks int64 `gorm:"column:blocks"`
+}
+
+func (p *BlockPoc) TableName() string {
+	return "t_block_poc"
+}
+
+const deadlineColumns = "count(*) as blocks, COALESCE(AVG(F_deadline), 0) as avg_deadline, " +
+	"CAST(COALESCE(MIN(F_deadline), 0) AS CHAR) as min_deadline, CAST(COALESCE(MAX(F_deadline), 0) AS CHAR) as max_deadline, " +
+	"COALESCE(MIN(F_timestamp), 0) as first_timestamp, COALESCE(MAX(F_timestamp), 0) as last_timestamp"
+
+// CreateBlockPoc write the poc of a block, the poc of a block hash forked before is set NORMAL again
+func CreateBlockPoc(db *gorm.DB, poc BlockPoc) (err error) {
+	ASSERT(poc.F_block_hash != "", "CreateBlockPoc, F_block_hash can't be nul")
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	sql := "INSERT INTO t_block_poc (F_block, F_block_hash, F_miner, F_timestamp, F_deadline, F_nonce, F_scoop, " +
+		"F_status, F_create_time, F_modify_time) VALUES (?,?,?,?,?,?,?,?,?,?)" +
+		" ON DUPLICATE KEY UPDATE F_deadline = VALUES(F_deadline), F_nonce = VALUES(F_nonce), " +
+		"F_scoop = VALUES(F_scoop), F_status = VALUES(F_status), F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, poc.F_block, poc.F_block_hash, poc.F_miner, poc.F_timestamp, poc.F_deadline, poc.F_nonce,
+		poc.F_scoop, NORMAL, newFormat, newFormat)
+	if rdb.Error != nil {
+		log.Debugf("CreateBlockPoc error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// UpdateBlockPocStatusByHeight set the NORMAL poc of height to status
+func UpdateBlockPocStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_block_poc").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
+
+// FindBlockPocByHash the poc of a block hash, forked ones included as the proof of a hash never changes
+func (p *BlockPoc) FindBlockPocByHash(db *gorm.DB, hash string) (poc BlockPoc, err error) {
+
+	rdb := db.Where("F_block_hash = ?", hash).First(&poc)
+	if rdb.RecordNotFound() {
+		err = errors.New(DATA_NOT_EXIST)
+	} else if rdb.Error != nil {
+		panic("FindBlockPocByHash error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return poc, err
+}
+
+// GetDeadlineStats the deadline of the blocks mined in [begin, end), miner empty for all
+func GetDeadlineStats(db *gorm.DB, begin int64, end int64, miner string) (stats DeadlineStats, err error) {
+	rdb := db.Table("t_block_poc").Where("F_timestamp >= ? and F_timestamp < ? and F_status = ?", begin, end, NORMAL)
+	if miner != "" {
+		rdb = rdb.Where("F_miner = ?", miner)
+	}
+
+	rdb = rdb.Select(deadlineColumns).Scan(&stats)
+	if rdb.Error != nil {
+		err = errors.New("GetDeadlineStats error:" + rdb.Error.Error())
+		return
+	}
+	stats.F_miner = miner
+
+	return stats, nil
+}
+
+// GetDeadlineStatsByMiner the deadline of each miner in [begin, end), most blocks first, and the number of miners
+func GetDeadlineStatsByMiner(db *gorm.DB, begin int64, end int64, offset int, size int) (stats []DeadlineStats, count int64, err error) {
+	rdb := db.Table("t_block_poc").Where("F_timestamp >= ? and F_timestamp < ? and F_status = ?", begin, end, NORMAL)
+
+	num := Count_number{}
+	cdb := rdb.Select(" count(distinct F_miner) as count ").Find(&num)
+	if cdb.Error != nil {
+		err = errors.New("GetDeadlineStatsByMiner error:" + cdb.Error.Error())
+		return
+	}
+
+	rdb = rdb.Select("F_miner, " + deadlineColumns).Group("F_miner").Order("blocks desc, F_miner").
+		Offset(offset).Limit(size).Scan(&stats)
+	if rdb.Error != nil {
+		err = errors.New("GetDeadlineStatsByMiner error:" + rdb.Error.Error())
+		return
+	}
+
+	return stats, num.Count, nil
+}
+
+// GetDeadlineStatsByTime the deadline of each interval seconds in [begin, end), miner empty for all
+func GetDeadlineStatsByTime(db *gorm.DB, begin int64, end int64, interval int64, miner string) (stats []DeadlineStats, err error) {
+	ASSERT(interval > 0, "GetDeadlineStatsByTime, interval must be positive")
+
+	rdb := db.Table("t_block_poc").Where("F_timestamp >= ? and F_timestamp < ? and F_status = ?", begin, end, NORMAL)
+	if miner != "" {
+		rdb = rdb.Where("F_miner = ?", miner)
+	}
+
+	rdb = rdb.Select("F_timestamp DIV ? * ? as period, "+deadlineColumns, interval, interval).
+		Group("period").Order("period").Scan(&stats)
+	if rdb.Error != nil {
+		err = errors.New("GetDeadlineStatsByTime error:" + rdb.Error.Error())
+		return
+	}
+	for i := range stats {
+		stats[i].F_miner = miner
+	}
+
+	return stats, nil
+}
+
+// GetScoopDistribution the blocks of each bucket scoops in [begin, end), miner empty for all
+func GetScoopDistribution(db *gorm.DB, begin int64, end int64, bucket int64, miner string) (counts []ScoopCount, err error) {
+	ASSERT(bucket > 0, "GetScoopDistribution, bucket must be positive")
+
+	rdb := db.Table("t_block_poc").Where("F_timestamp >= ? and F_timestamp < ? and F_status = ?", begin, end, NORMAL)
+	if miner != "" {
+		rdb = rdb.Where("F_miner = ?", miner)
+	}
+
+	rdb = rdb.Select("F_scoop DIV ? * ? as scoop, count(*) as blocks", bucket, bucket).
+		Group("scoop").Order("scoop").Scan(&counts)
+	if rdb.Error != nil {
+		err = errors.New("GetScoopDistribution error:" + rdb.Error.Error())
+		return
+	}
+
+	return counts, nil
+}
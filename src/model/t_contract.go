@@ -0,0 +1,96 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// 合约部署记录
+type Contract struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_address     string `gorm:"column:F_address"`
+	F_creator     string `gorm:"column:F_creator"`     //部署者
+	F_tx_hash     string `gorm:"column:F_tx_hash"`     //部署交易
+	F_block       int64  `gorm:"column:F_block"`       //部署高度
+	F_timestamp   int64  `gorm:"column:F_timestamp"`   //部署时间
+	F_code_hash   string `gorm:"column:F_code_hash"`   //keccak256(bytecode)
+	F_code_size   int64  `gorm:"column:F_code_size"`   //bytecode字节数
+	F_status      int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+func (ct *Contract) TableName() string {
+	return "t_contract"
+}
+
+// CreateContracts write the contracts deployed in a block, a contract deployed again after a fork is moved to its new block
+func CreateContracts(db *gorm.DB, contracts []Contract) (err error) {
+	if len(contracts) == 0 {
+		return nil
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	values := make([]string, 0, len(contracts))
+	args := make([]interface{}, 0, len(contracts)*10)
+	for _, ct := range contracts {
+		ASSERT(ct.F_address != "", "CreateContracts, F_address can't be nul")
+
+		values = append(values, "(?,?,?,?,?,?,?,?,?,?)")
+		args = append(args, ct.F_address, ct.F_creator, ct.F_tx_hash, ct.F_block, ct.F_timestamp, ct.F_code_hash,
+			ct.F_code_size, NORMAL, newFormat, newFormat)
+	}
+
+	sql := "INSERT INTO t_contract (F_address, F_creator, F_tx_hash, F_block, F_timestamp, F_code_hash, " +
+		"F_code_size, F_status, F_create_time, F_modify_time) VALUES " + strings.Join(values, ",") +
+		" ON DUPLICATE KEY UPDATE F_creator = VALUES(F_creator), F_tx_hash = VALUES(F_tx_hash), " +
+		"F_block = VALUES(F_block), F_timestamp = VALUES(F_timestamp), F_code_hash = VALUES(F_code_hash), " +
+		"F_code_size = VALUES(F_code_size), F_status = VALUES(F_status), F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, args...)
+	if rdb.Error != nil {
+		log.Debugf("CreateContracts error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// UpdateContractStatusByHeight set all NORMAL contracts deployed at height to status in one statement
+func UpdateContractStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_contract").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
+
+func (ct *Contract) FindContractByAddr(db *gorm.DB, addr string) (contract Contract, err error) {
+
+	rdb := db.Where("F_address = ? and F_status = ?", strings.ToLower(addr), NORMAL).First(&contract)
+	if rdb.RecordNotFound() {
+		err = errors.New(DATA_NOT_EXIST)
+	} else if rdb.Error != nil {
+		panic("FindContractByAddr error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return contract, err
+}
+
+// IsContract whether addr was deployed in an indexed block
+func IsContract(db *gorm.DB, addr string) (bool, error) {
+	num := Count_number{}
+	rdb := db.Table("t_contract").Where("F_address = ? and F_status = ?", strings.ToLower(addr), NORMAL).
+		Select(" count(*) as count ").Find(&num)
+	if rdb.Error != nil {
+		return false, errors.New("IsContract error:" + rdb.Error.Error())
+	}
+
+	return num.Count > 0, nil
+}
@@ -0,0 +1,233 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/jinzhu/gorm"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+	"strconv"
+	"time"
+)
+
+const DAYSECONDS = 86400
+
+// 每个UTC日的链上统计。实时同步每写入一个区块把它累加到当天，分叉时由NORMAL的区块和交易重新汇总当天；
+// 活跃地址和新地址要扫描交易，只在接近链头或当天结束时汇总
+type DailyStats struct {
+	F_id                uint64  `gorm:"column:F_id"`  //ID
+	F_day               int64   `gorm:"column:F_day"` //UTC 0点的时间戳
+	F_first_block       int64   `gorm:"column:F_first_block"`
+	F_last_block        int64   `gorm:"column:F_last_block"`
+	F_first_timestamp   int64   `gorm:"column:F_first_timestamp"`
+	F_last_timestamp    int64   `gorm:"column:F_last_timestamp"`
+	F_blocks            int64   `gorm:"column:F_blocks"`
+	F_txs               int64   `gorm:"column:F_txs"`
+	F_fees              string  `gorm:"column:F_fees"`              //decimal(65,0)
+	F_reward            string  `gorm:"column:F_reward"`            //decimal(65,0)
+	F_difficulty_sum    float64 `gorm:"column:F_difficulty_sum"`    //有难度的区块的难度之和
+	F_difficulty_blocks int64   `gorm:"column:F_difficulty_blocks"` //有难度的区块数，老版本同步的区块没有难度
+	F_gas_used          string  `gorm:"column:F_gas_used"`          //decimal(65,0)
+	F_gas_limit         string  `gorm:"column:F_gas_limit"`         //decimal(65,0)
+	F_avg_block_time    float64 `gorm:"column:F_avg_block_time"`    //秒，当天第一个到最后一个区块的平均间隔
+	F_avg_difficulty    float64 `gorm:"column:F_avg_difficulty"`
+	F_active_addresses  int64   `gorm:"column:F_active_addresses"` //交易的发送或接收地址
+	F_new_addresses     int64   `gorm:"column:F_new_addresses"`    //之前的区块没有出现过的活跃地址
+	F_gas_used_ratio    float64 `gorm:"column:F_gas_used_ratio"`   //gas_used之和/gas_limit之和
+	F_create_time       string  `gorm:"column:F_create_time"`      //创建时间
+	F_modify_time       string  `gorm:"column:F_modify_time"`      //修改时间
+}
+
+func (d *DailyStats) TableName() string {
+	return "t_daily_stats"
+}
+
+// DayOf the UTC day of a timestamp
+func DayOf(timestamp int64) int64 {
+	return timestamp - timestamp%DAYSECONDS
+}
+
+// dailyAverages the derived columns from the sums, set after them in an ON DUPLICATE KEY UPDATE
+const dailyAverages = "F_avg_block_time = IF(F_blocks > 1, (F_last_timestamp - F_first_timestamp) / (F_blocks - 1), 0), " +
+	"F_avg_difficulty = IF(F_difficulty_blocks > 0, F_difficulty_sum / F_difficulty_blocks, 0), " +
+	"F_gas_used_ratio = IF(F_gas_limit > 0, F_gas_used / F_gas_limit, 0)"
+
+// LockDay lock the t_daily_stats row of the UTC day of timestamp till the transaction ends, creating it
+// empty if needed. RefreshDailyStats summarizes the day under it, so the blocks AddDailyStats adds meanwhile
+// wait for it and are added to the summary, instead of being lost when the summary is written
+func LockDay(db *gorm.DB, timestamp int64) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	sql := "INSERT INTO t_daily_stats (F_day, F_create_time, F_modify_time) VALUES (?,?,?)" +
+		" ON DUPLICATE KEY UPDATE F_day = F_day"
+
+	rdb := db.Exec(sql, DayOf(timestamp), newFormat, newFormat)
+	if rdb.Error != nil {
+		log.Debugf("LockDay error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// AddDailyStats add a NORMAL block to its day in one statement, call it in the transaction writing the block.
+// The addresses of the day are not touched, see RefreshDailyAddresses
+func AddDailyStats(db *gorm.DB, block Block) (err error) {
+	fees, reward := decimalOrZero(block.F_fees), decimalOrZero(block.F_reward)
+	gasUsed, gasLimit := decimalOrZero(block.F_gas_used), decimalOrZero(block.F_gas_limit)
+	difficulty, difficultyBlocks := 0.0, int64(0)
+	if d, err := strconv.ParseFloat(block.F_difficulty, 64); err == nil {
+		difficulty, difficultyBlocks = d, 1
+	}
+	ratio := 0.0
+	if used, limit, err := parseFloats(gasUsed, gasLimit); err == nil && limit > 0 {
+		ratio = used / limit
+	}
+
+	//the assignments run in order and see the ones before, so F_blocks changes after the first and last block
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	sql := "INSERT INTO t_daily_stats (F_day, F_first_block, F_last_block, F_first_timestamp, F_last_timestamp, " +
+		"F_blocks, F_txs, F_fees, F_reward, F_difficulty_sum, F_difficulty_blocks, F_gas_used, F_gas_limit, " +
+		"F_avg_block_time, F_avg_difficulty, F_gas_used_ratio, F_create_time, F_modify_time) " +
+		"VALUES (?,?,?,?,?,1,?,?,?,?,?,?,?,0,?,?,?,?) ON DUPLICATE KEY UPDATE " +
+		"F_first_block = IF(F_blocks = 0, VALUES(F_first_block), LEAST(F_first_block, VALUES(F_first_block))), " +
+		"F_last_block = IF(F_blocks = 0, VALUES(F_last_block), GREATEST(F_last_block, VALUES(F_last_block))), " +
+		"F_first_timestamp = IF(F_blocks = 0, VALUES(F_first_timestamp), LEAST(F_first_timestamp, VALUES(F_first_timestamp))), " +
+		"F_last_timestamp = IF(F_blocks = 0, VALUES(F_last_timestamp), GREATEST(F_last_timestamp, VALUES(F_last_timestamp))), " +
+		"F_blocks = F_blocks + 1, F_txs = F_txs + VALUES(F_txs), " +
+		"F_fees = F_fees + VALUES(F_fees), F_reward = F_reward + VALUES(F_reward), " +
+		"F_difficulty_sum = F_difficulty_sum + VALUES(F_difficulty_sum), " +
+		"F_difficulty_blocks = F_difficulty_blocks + VALUES(F_difficulty_blocks), " +
+		"F_gas_used = F_gas_used + VALUES(F_gas_used), F_gas_limit = F_gas_limit + VALUES(F_gas_limit), " +
+		dailyAverages + ", F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, DayOf(block.F_timestamp), block.F_block, block.F_block, block.F_timestamp, block.F_timestamp,
+		block.F_txn, fees, reward, difficulty, difficultyBlocks, gasUsed, gasLimit, difficulty, ratio, newFormat, newFormat)
+	if rdb.Error != nil {
+		log.Debugf("AddDailyStats error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// RefreshDailyStats summarize the NORMAL blocks and transactions of the UTC day again under LockDay, with its
+// addresses. Call it in the transaction forking a block of the day, or in day order to rebuild the days
+func RefreshDailyStats(db *gorm.DB, day int64) (err error) {
+	day = DayOf(day)
+	if err = LockDay(db, day); err != nil {
+		return err
+	}
+
+	//read without locks, the blocks of a writer waiting for the day are not waited for in turn
+	type blockStats struct {
+		Blocks           int64   `gorm:"column:blocks"`
+		FirstBlock       int64   `gorm:"column:first_block"`
+		LastBlock        int64   `gorm:"column:last_block"`
+		FirstTimestamp   int64   `gorm:"column:first_timestamp"`
+		LastTimestamp    int64   `gorm:"column:last_timestamp"`
+		Txs              int64   `gorm:"column:txs"`
+		Fees             string  `gorm:"column:fees"`
+		Reward           string  `gorm:"column:reward"`
+		DifficultySum    float64 `gorm:"column:difficulty_sum"`
+		DifficultyBlocks int64   `gorm:"column:difficulty_blocks"`
+		GasUsed          string  `gorm:"column:gas_used"`
+		GasLimit         string  `gorm:"column:gas_limit"`
+	}
+	b := blockStats{}
+	rdb := db.Table("t_block").Where("F_timestamp >= ? and F_timestamp < ? and F_status = ?", day, day+DAYSECONDS, NORMAL).
+		Select("count(*) as blocks, COALESCE(MIN(F_block), -1) as first_block, COALESCE(MAX(F_block), -1) as last_block, " +
+			"COALESCE(MIN(F_timestamp), 0) as first_timestamp, COALESCE(MAX(F_timestamp), 0) as last_timestamp, " +
+			"CAST(COALESCE(SUM(F_txn), 0) AS SIGNED) as txs, " +
+			"CAST(COALESCE(SUM(CAST(NULLIF(F_fees, '') AS DECIMAL(65,0))), 0) AS CHAR) as fees, " +
+			"CAST(COALESCE(SUM(CAST(NULLIF(F_reward, '') AS DECIMAL(65,0))), 0) AS CHAR) as reward, " +
+			"COALESCE(SUM(IF(F_difficulty = '', 0, F_difficulty)), 0) as difficulty_sum, " +
+			"CAST(COALESCE(SUM(F_difficulty != ''), 0) AS SIGNED) as difficulty_blocks, " +
+			"CAST(COALESCE(SUM(CAST(NULLIF(F_gas_used, '') AS DECIMAL(65,0))), 0) AS CHAR) as gas_used, " +
+			"CAST(COALESCE(SUM(CAST(NULLIF(F_gas_limit, '') AS DECIMAL(65,0))), 0) AS CHAR) as gas_limit").Scan(&b)
+	if rdb.Error != nil {
+		return errors.New("RefreshDailyStats error:" + rdb.Error.Error())
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	sql := "UPDATE t_daily_stats SET F_first_block = ?, F_last_block = ?, F_first_timestamp = ?, F_last_timestamp = ?, " +
+		"F_blocks = ?, F_txs = ?, F_fees = ?, F_reward = ?, F_difficulty_sum = ?, F_difficulty_blocks = ?, " +
+		"F_gas_used = ?, F_gas_limit = ?, " + dailyAverages + ", F_modify_time = ? WHERE F_day = ?"
+
+	rdb = db.Exec(sql, b.FirstBlock, b.LastBlock, b.FirstTimestamp, b.LastTimestamp, b.Blocks, b.Txs, b.Fees, b.Reward,
+		b.DifficultySum, b.DifficultyBlocks, b.GasUsed, b.GasLimit, newFormat, day)
+	if rdb.Error != nil {
+		return errors.New("RefreshDailyStats error:" + rdb.Error.Error())
+	}
+
+	return RefreshDailyAddresses(db, day)
+}
+
+// RefreshDailyAddresses count the active and new addresses of the UTC day again. A new address has no NORMAL
+// transaction in an earlier block, so the count is right once every earlier day is indexed: a day written before
+// an earlier one has to be refreshed again, backfill does it in day order when it is done
+func RefreshDailyAddresses(db *gorm.DB, day int64) (err error) {
+	day = DayOf(day)
+
+	stats := DailyStats{}
+	rdb := db.Where("F_day = ?", day).First(&stats)
+	if rdb.RecordNotFound() || stats.F_blocks == 0 {
+		return nil
+	}
+	if rdb.Error != nil {
+		return errors.New("RefreshDailyAddresses error:" + rdb.Error.Error())
+	}
+
+	type addressStats struct {
+		Active int64 `gorm:"column:active"`
+		New    int64 `gorm:"column:new"`
+	}
+	a := addressStats{}
+	if stats.F_txs > 0 {
+		sql := "SELECT count(*) as active, CAST(COALESCE(SUM(" +
+			"NOT EXISTS (SELECT 1 FROM t_transaction f WHERE f.F_from = a.addr and f.F_block < ? and f.F_status = ?) and " +
+			"NOT EXISTS (SELECT 1 FROM t_transaction t WHERE t.F_to = a.addr and t.F_block < ? and t.F_status = ?)" +
+			"), 0) AS SIGNED) as new FROM (" +
+			"SELECT F_from as addr FROM t_transaction WHERE F_block >= ? and F_block <= ? and F_status = ? UNION " +
+			"SELECT F_to as addr FROM t_transaction WHERE F_block >= ? and F_block <= ? and F_status = ? and F_to != ''" +
+			") a"
+		rdb = db.Raw(sql, stats.F_first_block, NORMAL, stats.F_first_block, NORMAL,
+			stats.F_first_block, stats.F_last_block, NORMAL, stats.F_first_block, stats.F_last_block, NORMAL).Scan(&a)
+		if rdb.Error != nil {
+			return errors.New("RefreshDailyAddresses error:" + rdb.Error.Error())
+		}
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb = db.Exec("UPDATE t_daily_stats SET F_active_addresses = ?, F_new_addresses = ?, F_modify_time = ? WHERE F_day = ?",
+		a.Active, a.New, newFormat, day)
+	if rdb.Error != nil {
+		log.Debugf("RefreshDailyAddresses error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// decimalOrZero an integer column of a block, "0" if it was synced without it
+func decimalOrZero(value string) string {
+	if _, ok := big.NewInt(0).SetString(value, 10); !ok {
+		return "0"
+	}
+	return value
+}
+
+func parseFloats(a string, b string) (float64, float64, error) {
+	x, err := strconv.ParseFloat(a, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	y, err := strconv.ParseFloat(b, 64)
+	return x, y, err
+}
+
+// GetDailyStats the stats of the UTC days in [start, end), oldest first
+func GetDailyStats(db *gorm.DB, start int64, end int64) (days []DailyStats, err error) {
+	rdb := db.Where("F_day >= ? and F_day < ?", start, end).Order("F_day").Find(&days)
+	if rdb.Error != nil {
+		err = errors.New("GetDailyStats error:" + rdb.Error.Error())
+	}
+
+	return days, err
+}
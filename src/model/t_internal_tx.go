@@ -0,0 +1,104 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// 内部交易，由debug_traceTransaction的callTracer得到，不含交易本身
+type InternalTx struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_block       int64  `gorm:"column:F_block"`
+	F_block_hash  string `gorm:"column:F_block_hash"`
+	F_timestamp   int64  `gorm:"column:F_timestamp"`
+	F_tx_hash     string `gorm:"column:F_tx_hash"`
+	F_trace_index int64  `gorm:"column:F_trace_index"` //区块内序号，按交易顺序深度优先
+	F_depth       int64  `gorm:"column:F_depth"`       //1为交易直接发起的调用
+	F_type        string `gorm:"column:F_type"`        //CALL,CREATE,DELEGATECALL,STATICCALL,SELFDESTRUCT...
+	F_from        string `gorm:"column:F_from"`
+	F_to          string `gorm:"column:F_to"`
+	F_value       string `gorm:"column:F_value"`
+	F_error       string `gorm:"column:F_error"`       //空为成功，上层调用失败时为上层的错误
+	F_status      int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+func (it *InternalTx) TableName() string {
+	return "t_internal_tx"
+}
+
+// CreateInternalTxs write all internal calls of a block with multi-row inserts,
+// calls of a restored block are set NORMAL again
+func CreateInternalTxs(db *gorm.DB, internals []InternalTx) (err error) {
+	const rowsPerInsert = 500
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	for start := 0; start < len(internals); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(internals) {
+			end = len(internals)
+		}
+
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*14)
+		for _, it := range internals[start:end] {
+			ASSERT(it.F_block_hash != "", "CreateInternalTxs, F_block_hash can't be nul")
+
+			values = append(values, "(?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
+			args = append(args, it.F_block, it.F_block_hash, it.F_timestamp, it.F_tx_hash, it.F_trace_index, it.F_depth,
+				it.F_type, it.F_from, it.F_to, it.F_value, it.F_error, NORMAL, newFormat, newFormat)
+		}
+
+		sql := "INSERT INTO t_internal_tx (F_block, F_block_hash, F_timestamp, F_tx_hash, F_trace_index, F_depth, " +
+			"F_type, F_from, F_to, F_value, F_error, F_status, F_create_time, F_modify_time) VALUES " +
+			strings.Join(values, ",") +
+			" ON DUPLICATE KEY UPDATE F_status = VALUES(F_status), F_modify_time = VALUES(F_modify_time)"
+
+		rdb := db.Exec(sql, args...)
+		if rdb.Error != nil {
+			log.Debugf("CreateInternalTxs error:%s", rdb.Error.Error())
+			return rdb.Error
+		}
+	}
+
+	return nil
+}
+
+// UpdateInternalTxStatusByHeight set all NORMAL internal calls of height to status in one statement
+func UpdateInternalTxStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_internal_tx").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
+
+// GetInternalTxsByAddr the NORMAL internal calls moving value from or to addr, newest first
+func GetInternalTxsByAddr(db *gorm.DB, addr string, offset int, size int) (internals []InternalTx, err error) {
+	rdb := db.Where("F_status = ? and (F_from = ? or F_to = ?) and F_value <> '0'", NORMAL, addr, addr).
+		Order("F_block desc, F_trace_index desc").Offset(offset).Limit(size).Find(&internals)
+	if rdb.Error != nil {
+		err = errors.New("GetInternalTxsByAddr error:" + rdb.Error.Error())
+		return
+	}
+
+	return internals, nil
+}
+
+func GetInternalTxsCountByAddr(db *gorm.DB, addr string) (count int64, err error) {
+	num := Count_number{}
+	rdb := db.Table("t_internal_tx").Where("F_status = ? and (F_from = ? or F_to = ?) and F_value <> '0'", NORMAL, addr, addr).
+		Select(" count(*) as count ").Find(&num)
+	if rdb.Error != nil {
+		err = errors.New("GetInternalTxsCountByAddr error:" + rdb.Error.Error())
+		return
+	}
+
+	return num.Count, nil
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+type Log struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_block       int64  `gorm:"column:F_block"`
+	F_block_hash  string `gorm:"column:F_block_hash"`
+	F_timestamp   int64  `gorm:"column:F_timestamp"`
+	F_tx_hash     string `gorm:"column:F_tx_hash"`
+	F_tx_index    int64  `gorm:"column:F_tx_index"`
+	F_log_index   int64  `gorm:"column:F_log_index"` //区块内序号
+	F_address     string `gorm:"column:F_address"`   //产生日志的合约
+	F_topic0      string `gorm:"column:F_topic0"`
+	F_topic1      string `gorm:"column:F_topic1"`
+	F_topic2      string `gorm:"column:F_topic2"`
+	F_topic3      string `gorm:"column:F_topic3"`
+	F_data        string `gorm:"column:F_data"`
+	F_status      int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+// LogFilter is the eth_getLogs like condition of QueryLogs, empty fields match everything
+type LogFilter struct {
+	Address   string
+	Topics    [4]string
+	FromBlock int64
+	ToBlock   int64 //0 for no upper bound
+}
+
+func (l *Log) TableName() string {
+	return "t_log"
+}
+
+// Topics the non empty topics in order
+func (l *Log) Topics() []string {
+	topics := make([]string, 0, 4)
+	for _, topic := range []string{l.F_topic0, l.F_topic1, l.F_topic2, l.F_topic3} {
+		if topic == "" {
+			break
+		}
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// CreateLogs write all logs of a block with multi-row inserts, logs of a restored block are set NORMAL again
+func CreateLogs(db *gorm.DB, logs []Log) (err error) {
+	const rowsPerInsert = 500
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	for start := 0; start < len(logs); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(logs) {
+			end = len(logs)
+		}
+
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*16)
+		for _, l := range logs[start:end] {
+			ASSERT(l.F_block_hash != "", "CreateLogs, F_block_hash can't be nul")
+
+			values = append(values, "(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
+			args = append(args, l.F_block, l.F_block_hash, l.F_timestamp, l.F_tx_hash, l.F_tx_index, l.F_log_index,
+				l.F_address, l.F_topic0, l.F_topic1, l.F_topic2, l.F_topic3, l.F_data, NORMAL, newFormat, newFormat)
+		}
+
+		sql := "INSERT INTO t_log (F_block, F_block_hash, F_timestamp, F_tx_hash, F_tx_index, F_log_index, " +
+			"F_address, F_topic0, F_topic1, F_topic2, F_topic3, F_data, F_status, F_create_time, F_modify_time) VALUES " +
+			strings.Join(values, ",") +
+			" ON DUPLICATE KEY UPDATE F_status = VALUES(F_status), F_modify_time = VALUES(F_modify_time)"
+
+		rdb := db.Exec(sql, args...)
+		if rdb.Error != nil {
+			log.Debugf("CreateLogs error:%s", rdb.Error.Error())
+			return rdb.Error
+		}
+	}
+
+	return nil
+}
+
+// UpdateLogStatusByHeight set all NORMAL logs of height to status in one statement
+func UpdateLogStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_log").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
+
+// QueryLogs the NORMAL logs matching filter, in chain order, and the count of all matches
+func QueryLogs(db *gorm.DB, filter LogFilter, offset int, size int) (logs []Log, count int64, err error) {
+	rdb := db.Table("t_log").Where("F_status = ? and F_block >= ?", NORMAL, filter.FromBlock)
+	if filter.ToBlock > 0 {
+		rdb = rdb.Where("F_block <= ?", filter.ToBlock)
+	}
+	if filter.Address != "" {
+		rdb = rdb.Where("F_address = ?", filter.Address)
+	}
+	for i, topic := range filter.Topics {
+		if topic != "" {
+			rdb = rdb.Where("F_topic"+string('0'+rune(i))+" = ?", topic)
+		}
+	}
+
+	num := Count_number{}
+	cdb := rdb.Select(" count(*) as count ").Find(&num)
+	if cdb.Error != nil {
+		err = errors.New("QueryLogs error:" + cdb.Error.Error())
+		return
+	}
+
+	rdb = rdb.Order("F_block asc, F_log_index asc").Offset(offset).Limit(size).Find(&logs)
+	if rdb.Error != nil {
+		err = errors.New("QueryLogs error:" + rdb.Error.Error())
+		return
+	}
+
+	return logs, num.Count, nil
+}
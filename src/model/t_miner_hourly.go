@@ -0,0 +1,170 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const HOURSECONDS = 3600
+
+// 每个矿工每小时的出块统计，实时同步每写入一个区块累加一次，分叉时由NORMAL的区块重新汇总该小时
+type MinerHourly struct {
+	F_id                uint64  `gorm:"column:F_id"`   //ID
+	F_hour              int64   `gorm:"column:F_hour"` //整点的时间戳
+	F_miner             string  `gorm:"column:F_miner"`
+	F_blocks            int64   `gorm:"column:F_blocks"`
+	F_reward            string  `gorm:"column:F_reward"`            //decimal(65,0)
+	F_fees              string  `gorm:"column:F_fees"`              //decimal(65,0)
+	F_difficulty_sum    float64 `gorm:"column:F_difficulty_sum"`    //有难度的区块的难度之和
+	F_difficulty_blocks int64   `gorm:"column:F_difficulty_blocks"` //有难度的区块数，老版本同步的区块没有难度
+	F_create_time       string  `gorm:"column:F_create_time"`       //创建时间
+	F_modify_time       string  `gorm:"column:F_modify_time"`       //修改时间
+}
+
+// MinerRank is the blocks, reward and fees of a miner summed over the hours of a window
+type MinerRank struct {
+	F_miner string `gorm:"column:F_miner"`
+	Blocks  int64  `gorm:"column:blocks"`
+	Reward  string `gorm:"column:reward"`
+	Fees    string `gorm:"column:fees"`
+}
+
+// MinerWindowTotal is the blocks and difficulty of all miners over the hours of a window
+type MinerWindowTotal struct {
+	Blocks           int64   `gorm:"column:blocks"`
+	DifficultySum    float64 `gorm:"column:difficulty_sum"`
+	DifficultyBlocks int64   `gorm:"column:difficulty_blocks"`
+}
+
+// MinerRankOrders the ranking orders of GetTopMiners
+var MinerRankOrders = map[string]string{
+	"blocks": "blocks desc, SUM(F_reward) desc, F_miner",
+	"reward": "SUM(F_reward) desc, blocks desc, F_miner",
+	"fees":   "SUM(F_fees) desc, blocks desc, F_miner",
+}
+
+func (h *MinerHourly) TableName() string {
+	return "t_miner_hourly"
+}
+
+// HourOf the hour of a timestamp
+func HourOf(timestamp int64) int64 {
+	return timestamp - timestamp%HOURSECONDS
+}
+
+// AddMinerHourly add a NORMAL block to the hour of its miner in one statement, call it in the transaction
+// writing the block after AddDailyStats, which holds the day like RefreshMinerHourly does
+func AddMinerHourly(db *gorm.DB, block Block) (err error) {
+	difficulty, difficultyBlocks := 0.0, int64(0)
+	if d, err := strconv.ParseFloat(block.F_difficulty, 64); err == nil {
+		difficulty, difficultyBlocks = d, 1
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	sql := "INSERT INTO t_miner_hourly (F_hour, F_miner, F_blocks, F_reward, F_fees, F_difficulty_sum, F_difficulty_blocks, " +
+		"F_create_time, F_modify_time) VALUES (?,?,1,?,?,?,?,?,?) ON DUPLICATE KEY UPDATE F_blocks = F_blocks + 1, " +
+		"F_reward = F_reward + VALUES(F_reward), F_fees = F_fees + VALUES(F_fees), " +
+		"F_difficulty_sum = F_difficulty_sum + VALUES(F_difficulty_sum), " +
+		"F_difficulty_blocks = F_difficulty_blocks + VALUES(F_difficulty_blocks), F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, HourOf(block.F_timestamp), block.F_miner, decimalOrZero(block.F_reward), decimalOrZero(block.F_fees),
+		difficulty, difficultyBlocks, newFormat, newFormat)
+	if rdb.Error != nil {
+		log.Debugf("AddMinerHourly error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// RefreshMinerHourly summarize the NORMAL blocks of the hour again under the LockDay of its day, call it in
+// the transaction forking a block of the hour, or to rebuild the hours. The blocks are read without locks,
+// an INSERT ... SELECT would wait for the block rows of another writer while holding the day
+func RefreshMinerHourly(db *gorm.DB, timestamp int64) (err error) {
+	hour := HourOf(timestamp)
+	if err = LockDay(db, hour); err != nil {
+		return err
+	}
+
+	miners := make([]MinerHourly, 0)
+	rdb := db.Table("t_block").Where("F_timestamp >= ? and F_timestamp < ? and F_status = ?", hour, hour+HOURSECONDS, NORMAL).
+		Select("F_miner, count(*) as F_blocks, " +
+			"CAST(COALESCE(SUM(CAST(NULLIF(F_reward, '') AS DECIMAL(65,0))), 0) AS CHAR) as F_reward, " +
+			"CAST(COALESCE(SUM(CAST(NULLIF(F_fees, '') AS DECIMAL(65,0))), 0) AS CHAR) as F_fees, " +
+			"COALESCE(SUM(IF(F_difficulty = '', 0, F_difficulty)), 0) as F_difficulty_sum, " +
+			"CAST(SUM(F_difficulty != '') AS SIGNED) as F_difficulty_blocks").Group("F_miner").Scan(&miners)
+	if rdb.Error != nil {
+		log.Debugf("RefreshMinerHourly error:%s", rdb.Error.Error())
+		return rdb.Error
+	}
+
+	rdb = db.Exec("DELETE FROM t_miner_hourly WHERE F_hour = ?", hour)
+	if rdb.Error != nil {
+		log.Debugf("RefreshMinerHourly error:%s", rdb.Error.Error())
+		return rdb.Error
+	}
+	if len(miners) == 0 {
+		return nil
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	values := make([]string, 0, len(miners))
+	args := make([]interface{}, 0, len(miners)*9)
+	for _, m := range miners {
+		values = append(values, "(?,?,?,?,?,?,?,?,?)")
+		args = append(args, hour, m.F_miner, m.F_blocks, m.F_reward, m.F_fees, m.F_difficulty_sum, m.F_difficulty_blocks,
+			newFormat, newFormat)
+	}
+
+	sql := "INSERT INTO t_miner_hourly (F_hour, F_miner, F_blocks, F_reward, F_fees, F_difficulty_sum, F_difficulty_blocks, " +
+		"F_create_time, F_modify_time) VALUES " + strings.Join(values, ",")
+
+	rdb = db.Exec(sql, args...)
+	if rdb.Error != nil {
+		log.Debugf("RefreshMinerHourly error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// GetTopMiners the miners of the hours from start on ranked by order, one of MinerRankOrders, and the number of miners
+func GetTopMiners(db *gorm.DB, start int64, order string, offset int, size int) (ranks []MinerRank, count int64, err error) {
+	orderBy, ok := MinerRankOrders[order]
+	if !ok {
+		return nil, 0, errors.New("GetTopMiners error:unknown order " + order)
+	}
+
+	rdb := db.Table("t_miner_hourly").Where("F_hour >= ?", start)
+
+	num := Count_number{}
+	cdb := rdb.Select(" count(distinct F_miner) as count ").Find(&num)
+	if cdb.Error != nil {
+		err = errors.New("GetTopMiners error:" + cdb.Error.Error())
+		return
+	}
+
+	rdb = rdb.Select("F_miner, CAST(SUM(F_blocks) AS SIGNED) as blocks, CAST(SUM(F_reward) AS CHAR) as reward, " +
+		"CAST(SUM(F_fees) AS CHAR) as fees").Group("F_miner").Order(orderBy).Offset(offset).Limit(size).Scan(&ranks)
+	if rdb.Error != nil {
+		err = errors.New("GetTopMiners error:" + rdb.Error.Error())
+		return
+	}
+
+	return ranks, num.Count, nil
+}
+
+// GetMinerWindowTotal the blocks and difficulty of all miners of the hours from start on
+func GetMinerWindowTotal(db *gorm.DB, start int64) (total MinerWindowTotal, err error) {
+	rdb := db.Table("t_miner_hourly").Where("F_hour >= ?", start).
+		Select("CAST(COALESCE(SUM(F_blocks), 0) AS SIGNED) as blocks, COALESCE(SUM(F_difficulty_sum), 0) as difficulty_sum, " +
+			"CAST(COALESCE(SUM(F_difficulty_blocks), 0) AS SIGNED) as difficulty_blocks").Scan(&total)
+	if rdb.Error != nil {
+		err = errors.New("GetMinerWindowTotal error:" + rdb.Error.Error())
+	}
+
+	return total, err
+}
@@ -67,25 +67,47 @@ func (r *MinerReward) FindRewardByMiner(db *gorm.DB, addr string) (reward MinerR
 	return reward, err
 }
 
-func (r *MinerReward) UpdateMinerReward(db *gorm.DB) (err error) {
-	updateinfo := map[string]interface{}{"F_total_reward": r.F_total_reward}
-	return r.updateMinerRewardColumn(db, updateinfo)
-}
+//AddMinerReward add the amounts of a t_reward_ledger entry to the totals of miner in one statement,
+//call it in the transaction appending the entry. The totals are decimal strings, they are added as decimals
+func AddMinerReward(db *gorm.DB, miner string, reward string, fees string) (err error) {
+	util.ASSERT(miner != "", "AddMinerReward, miner can't be nul")
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	sql := "INSERT INTO t_miner_reward (F_miner, F_total_reward, F_total_fees, F_create_time, F_modify_time) " +
+		"VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE " +
+		"F_total_reward = " + addDecimal("F_total_reward") + ", F_total_fees = " + addDecimal("F_total_fees") + ", " +
+		"F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, miner, reward, fees, newFormat, newFormat)
+	if rdb.Error != nil {
+		log.Debugf("AddMinerReward,miner:%s error:%s", miner, rdb.Error.Error())
+	}
 
-func (r *MinerReward) updateMinerRewardColumn(db *gorm.DB, updateinfo map[string]interface{}) (err error) {
+	return rdb.Error
+}
 
-	log.Debugf("updateMinerRewardColumn F_id:%d,miner:%s,%+v", r.F_id, r.F_miner, updateinfo)
+//addDecimal the sum of a total column and its inserted value, a total never written counts as 0
+func addDecimal(column string) string {
+	return "CAST(CAST(COALESCE(NULLIF(" + column + ", ''), '0') AS DECIMAL(65,0)) + " +
+		"CAST(VALUES(" + column + ") AS DECIMAL(65,0)) AS CHAR)"
+}
 
-	tx := db.Begin()
+//RefreshMinerReward set the totals of miner to the sums of its t_reward_ledger entries in one statement,
+//verify-rewards repairs the totals with it, the block writers add each entry with AddMinerReward
+func RefreshMinerReward(db *gorm.DB, miner string) (err error) {
+	util.ASSERT(miner != "", "RefreshMinerReward, miner can't be nul")
 
-	rdb := tx.Where("F_miner = ?", r.F_miner).Model(&r).Update(updateinfo)
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	sql := "INSERT INTO t_miner_reward (F_miner, F_total_reward, F_total_fees, F_create_time, F_modify_time) " +
+		"SELECT ?, " + rewardTotalColumns + ", ?, ? FROM t_reward_ledger WHERE F_miner = ?" +
+		" ON DUPLICATE KEY UPDATE F_total_reward = VALUES(F_total_reward), F_total_fees = VALUES(F_total_fees), " +
+		"F_modify_time = VALUES(F_modify_time)"
 
+	rdb := db.Exec(sql, miner, newFormat, newFormat, miner)
 	if rdb.Error != nil {
-		tx.Rollback()
-		return rdb.Error
+		log.Debugf("RefreshMinerReward,miner:%s error:%s", miner, rdb.Error.Error())
 	}
 
-	tx.Commit()
 	return rdb.Error
 }
 
@@ -0,0 +1,192 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 抵押合约的mortgage(address,uint256)和redeem(address,uint256)调用，只含交易直接调用的
+type Mortgage struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_tx_hash     string `gorm:"column:F_tx_hash"`
+	F_block       int64  `gorm:"column:F_block"`
+	F_block_hash  string `gorm:"column:F_block_hash"`
+	F_timestamp   int64  `gorm:"column:F_timestamp"`
+	F_tx_index    int64  `gorm:"column:F_tx_index"`
+	F_from        string `gorm:"column:F_from"`        //交易发送者
+	F_addr        string `gorm:"column:F_addr"`        //抵押或赎回的地址，调用的第一个参数
+	F_type        int64  `gorm:"column:F_type"`        //TX_TYPE_ME_MORTGAGE,TX_TYPE_ME_REDEEM
+	F_amount      string `gorm:"column:F_amount"`      //调用的第二个参数，decimal(65,0)
+	F_value       string `gorm:"column:F_value"`       //交易的value
+	F_success     int    `gorm:"column:F_success"`     //1 成功，0 失败，失败的不计入抵押
+	F_status      int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+// MortgageStake is the stake of an address summed from the ledger
+type MortgageStake struct {
+	F_addr    string `gorm:"column:F_addr"`
+	Stake     string `gorm:"column:stake"`     //抵押减赎回
+	Mortgaged string `gorm:"column:mortgaged"` //累计抵押
+	Redeemed  string `gorm:"column:redeemed"`  //累计赎回
+}
+
+func (m *Mortgage) TableName() string {
+	return "t_mortgage"
+}
+
+// stakeColumns the sums of the NORMAL successful rows, cast so a decimal sum scans into a string
+func stakeColumns() string {
+	mortgage := strconv.FormatInt(TX_TYPE_ME_MORTGAGE, 10)
+	return "CAST(COALESCE(SUM(IF(F_type = " + mortgage + ", F_amount, -F_amount)), 0) AS CHAR) as stake, " +
+		"CAST(COALESCE(SUM(IF(F_type = " + mortgage + ", F_amount, 0)), 0) AS CHAR) as mortgaged, " +
+		"CAST(COALESCE(SUM(IF(F_type = " + mortgage + ", 0, F_amount)), 0) AS CHAR) as redeemed"
+}
+
+// CreateMortgages write the mortgage calls of a block, a call forked before is moved to this block and set NORMAL
+func CreateMortgages(db *gorm.DB, mortgages []Mortgage) (err error) {
+	if len(mortgages) == 0 {
+		return nil
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	values := make([]string, 0, len(mortgages))
+	args := make([]interface{}, 0, len(mortgages)*14)
+	for _, m := range mortgages {
+		ASSERT(m.F_tx_hash != "", "CreateMortgages, F_tx_hash can't be nul")
+
+		values = append(values, "(?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
+		args = append(args, m.F_tx_hash, m.F_block, m.F_block_hash, m.F_timestamp, m.F_tx_index, m.F_from, m.F_addr,
+			m.F_type, m.F_amount, m.F_value, m.F_success, NORMAL, newFormat, newFormat)
+	}
+
+	sql := "INSERT INTO t_mortgage (F_tx_hash, F_block, F_block_hash, F_timestamp, F_tx_index, F_from, F_addr, " +
+		"F_type, F_amount, F_value, F_success, F_status, F_create_time, F_modify_time) VALUES " + strings.Join(values, ",") +
+		" ON DUPLICATE KEY UPDATE F_block = VALUES(F_block), F_block_hash = VALUES(F_block_hash), " +
+		"F_timestamp = VALUES(F_timestamp), F_tx_index = VALUES(F_tx_index), F_success = VALUES(F_success), " +
+		"F_status = VALUES(F_status), F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, args...)
+	if rdb.Error != nil {
+		log.Debugf("CreateMortgages error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// UpdateMortgageStatusByHeight set all NORMAL mortgage calls of height to status in one statement
+func UpdateMortgageStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_mortgage").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
+
+// GetMortgageStake the stake of addr up to height (-1 for all)
+func GetMortgageStake(db *gorm.DB, addr string, height int64) (stake MortgageStake, err error) {
+	rdb := db.Table("t_mortgage").Where("F_addr = ? and F_status = ? and F_success = 1", addr, NORMAL)
+	if height >= 0 {
+		rdb = rdb.Where("F_block <= ?", height)
+	}
+
+	rdb = rdb.Select(stakeColumns()).Scan(&stake)
+	if rdb.Error != nil {
+		err = errors.New("GetMortgageStake error:" + rdb.Error.Error())
+		return
+	}
+	stake.F_addr = addr
+
+	return stake, nil
+}
+
+// GetMortgageStakeAt the stake of addr right after the call at (block, txIndex)
+func GetMortgageStakeAt(db *gorm.DB, addr string, block int64, txIndex int64) (stake MortgageStake, err error) {
+	rdb := db.Table("t_mortgage").Where("F_addr = ? and F_status = ? and F_success = 1", addr, NORMAL).
+		Where("(F_block < ? or (F_block = ? and F_tx_index <= ?))", block, block, txIndex).
+		Select(stakeColumns()).Scan(&stake)
+	if rdb.Error != nil {
+		err = errors.New("GetMortgageStakeAt error:" + rdb.Error.Error())
+		return
+	}
+	stake.F_addr = addr
+
+	return stake, nil
+}
+
+// GetMortgageStakesBefore the stakes of addrs from the calls before timestamp, addresses without a call are left out
+func GetMortgageStakesBefore(db *gorm.DB, addrs []string, timestamp int64) (stakes []MortgageStake, err error) {
+	if len(addrs) == 0 {
+		return stakes, nil
+	}
+
+	rdb := db.Table("t_mortgage").Where("F_addr in (?) and F_status = ? and F_success = 1 and F_timestamp < ?", addrs, NORMAL, timestamp).
+		Select("F_addr, " + stakeColumns()).Group("F_addr").Scan(&stakes)
+	if rdb.Error != nil {
+		err = errors.New("GetMortgageStakesBefore error:" + rdb.Error.Error())
+		return
+	}
+
+	return stakes, nil
+}
+
+// GetTotalStake the stake of all addresses up to height, to reconcile with eth_getTotalMortgage
+func GetTotalStake(db *gorm.DB, height int64) (stake MortgageStake, err error) {
+	rdb := db.Table("t_mortgage").Where("F_status = ? and F_success = 1 and F_block <= ?", NORMAL, height).
+		Select(stakeColumns()).Scan(&stake)
+	if rdb.Error != nil {
+		err = errors.New("GetTotalStake error:" + rdb.Error.Error())
+		return
+	}
+
+	return stake, nil
+}
+
+// GetMortgageHistory the NORMAL mortgage calls of addr, failed ones included, newest first, and their count
+func GetMortgageHistory(db *gorm.DB, addr string, offset int, size int) (mortgages []Mortgage, count int64, err error) {
+	rdb := db.Table("t_mortgage").Where("F_addr = ? and F_status = ?", addr, NORMAL)
+
+	num := Count_number{}
+	cdb := rdb.Select(" count(*) as count ").Find(&num)
+	if cdb.Error != nil {
+		err = errors.New("GetMortgageHistory error:" + cdb.Error.Error())
+		return
+	}
+
+	rdb = rdb.Order("F_block desc, F_tx_index desc").Offset(offset).Limit(size).Find(&mortgages)
+	if rdb.Error != nil {
+		err = errors.New("GetMortgageHistory error:" + rdb.Error.Error())
+		return
+	}
+
+	return mortgages, num.Count, nil
+}
+
+// GetTopStakers the addresses with a positive stake, largest first, and their count
+func GetTopStakers(db *gorm.DB, offset int, size int) (stakes []MortgageStake, count int64, err error) {
+	grouped := "SELECT F_addr, " + stakeColumns() + " FROM t_mortgage WHERE F_status = ? and F_success = 1 " +
+		"GROUP BY F_addr HAVING SUM(IF(F_type = ?, F_amount, -F_amount)) > 0"
+
+	num := Count_number{}
+	rdb := db.Raw("SELECT count(*) as count FROM ("+grouped+") t", NORMAL, TX_TYPE_ME_MORTGAGE).Scan(&num)
+	if rdb.Error != nil {
+		err = errors.New("GetTopStakers error:" + rdb.Error.Error())
+		return
+	}
+
+	rdb = db.Raw(grouped+" ORDER BY SUM(IF(F_type = ?, F_amount, -F_amount)) desc LIMIT ? OFFSET ?",
+		NORMAL, TX_TYPE_ME_MORTGAGE, TX_TYPE_ME_MORTGAGE, size, offset).Scan(&stakes)
+	if rdb.Error != nil {
+		err = errors.New("GetTopStakers error:" + rdb.Error.Error())
+		return
+	}
+
+	return stakes, num.Count, nil
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// ERC-721当前持有者，由最新的转账决定
+type NftOwner struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_token       string `gorm:"column:F_token"`
+	F_token_id    string `gorm:"column:F_token_id"`
+	F_owner       string `gorm:"column:F_owner"`
+	F_block       int64  `gorm:"column:F_block"`       //最新转账的高度
+	F_log_index   int64  `gorm:"column:F_log_index"`   //最新转账的日志序号
+	F_token_uri   string `gorm:"column:F_token_uri"`   //空为未知
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+func (no *NftOwner) TableName() string {
+	return "t_nft_owner"
+}
+
+// UpdateNftOwners move each token id to the owner of its row, unless databases already has a later transfer,
+// so blocks written out of order (backfill) end up with the owner of the latest one
+func UpdateNftOwners(db *gorm.DB, owners []NftOwner) (err error) {
+	const rowsPerInsert = 500
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	for start := 0; start < len(owners); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(owners) {
+			end = len(owners)
+		}
+
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*8)
+		for _, no := range owners[start:end] {
+			ASSERT(no.F_token != "", "UpdateNftOwners, F_token can't be nul")
+
+			values = append(values, "(?,?,?,?,?,?,?,?)")
+			args = append(args, no.F_token, no.F_token_id, no.F_owner, no.F_block, no.F_log_index, no.F_token_uri,
+				newFormat, newFormat)
+		}
+
+		//F_owner and F_log_index compare with the old F_block, so F_block is assigned last
+		later := "(VALUES(F_block) > F_block OR (VALUES(F_block) = F_block AND VALUES(F_log_index) >= F_log_index))"
+		sql := "INSERT INTO t_nft_owner (F_token, F_token_id, F_owner, F_block, F_log_index, F_token_uri, " +
+			"F_create_time, F_modify_time) VALUES " + strings.Join(values, ",") +
+			" ON DUPLICATE KEY UPDATE " +
+			"F_owner = IF(" + later + ", VALUES(F_owner), F_owner), " +
+			"F_log_index = IF(" + later + ", VALUES(F_log_index), F_log_index), " +
+			"F_block = GREATEST(F_block, VALUES(F_block)), " +
+			"F_token_uri = IF(VALUES(F_token_uri) = '', F_token_uri, VALUES(F_token_uri)), " +
+			"F_modify_time = VALUES(F_modify_time)"
+
+		rdb := db.Exec(sql, args...)
+		if rdb.Error != nil {
+			log.Debugf("UpdateNftOwners error:%s", rdb.Error.Error())
+			return rdb.Error
+		}
+	}
+
+	return nil
+}
+
+// ResetNftOwner set the owner of a token id back to its latest NORMAL transfer, used after a fork
+func ResetNftOwner(db *gorm.DB, token string, tokenId string) (err error) {
+	transfer, err := (&NftTransfer{}).FindLastNftTransfer(db, token, tokenId)
+	if err != nil && err.Error() != DATA_NOT_EXIST {
+		return err
+	}
+
+	//only minted in the forked block
+	if err != nil {
+		rdb := db.Where("F_token = ? and F_token_id = ?", token, tokenId).Delete(NftOwner{})
+		return rdb.Error
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_nft_owner").Where("F_token = ? and F_token_id = ?", token, tokenId).
+		Updates(map[string]interface{}{
+			"F_owner":       transfer.F_to,
+			"F_block":       transfer.F_block,
+			"F_log_index":   transfer.F_log_index,
+			"F_modify_time": newFormat,
+		})
+
+	return rdb.Error
+}
+
+func (no *NftOwner) FindNftOwner(db *gorm.DB, token string, tokenId string) (owner NftOwner, err error) {
+
+	rdb := db.Where("F_token = ? and F_token_id = ?", token, tokenId).First(&owner)
+	if rdb.RecordNotFound() {
+		err = errors.New(DATA_NOT_EXIST)
+	} else if rdb.Error != nil {
+		panic("FindNftOwner error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return owner, err
+}
+
+// GetNftsByOwner the token ids held by owner, latest received first, and their count
+func GetNftsByOwner(db *gorm.DB, owner string, token string, offset int, size int) (owners []NftOwner, count int64, err error) {
+	rdb := db.Table("t_nft_owner").Where("F_owner = ?", owner)
+	if token != "" {
+		rdb = rdb.Where("F_token = ?", token)
+	}
+
+	num := Count_number{}
+	cdb := rdb.Select(" count(*) as count ").Find(&num)
+	if cdb.Error != nil {
+		err = errors.New("GetNftsByOwner error:" + cdb.Error.Error())
+		return
+	}
+
+	rdb = rdb.Order("F_block desc, F_log_index desc").Offset(offset).Limit(size).Find(&owners)
+	if rdb.Error != nil {
+		err = errors.New("GetNftsByOwner error:" + rdb.Error.Error())
+		return
+	}
+
+	return owners, num.Count, nil
+}
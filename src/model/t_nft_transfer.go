@@ -0,0 +1,140 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// ERC-721转账，由带tokenId的Transfer日志解析
+type NftTransfer struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_block       int64  `gorm:"column:F_block"`
+	F_block_hash  string `gorm:"column:F_block_hash"`
+	F_timestamp   int64  `gorm:"column:F_timestamp"`
+	F_tx_hash     string `gorm:"column:F_tx_hash"`
+	F_log_index   int64  `gorm:"column:F_log_index"` //区块内日志序号
+	F_token       string `gorm:"column:F_token"`     //ERC-721合约
+	F_token_id    string `gorm:"column:F_token_id"`  //十进制
+	F_from        string `gorm:"column:F_from"`
+	F_to          string `gorm:"column:F_to"`
+	F_status      int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+// NftFilter is the condition of GetNftTransfers, empty fields match everything
+type NftFilter struct {
+	Token   string
+	TokenId string
+	Addr    string //from or to
+}
+
+func (nt *NftTransfer) TableName() string {
+	return "t_nft_transfer"
+}
+
+// CreateNftTransfers write all ERC-721 transfers of a block with multi-row inserts,
+// transfers of a restored block are set NORMAL again
+func CreateNftTransfers(db *gorm.DB, transfers []NftTransfer) (err error) {
+	const rowsPerInsert = 500
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	for start := 0; start < len(transfers); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(transfers) {
+			end = len(transfers)
+		}
+
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*12)
+		for _, nt := range transfers[start:end] {
+			ASSERT(nt.F_block_hash != "", "CreateNftTransfers, F_block_hash can't be nul")
+
+			values = append(values, "(?,?,?,?,?,?,?,?,?,?,?,?)")
+			args = append(args, nt.F_block, nt.F_block_hash, nt.F_timestamp, nt.F_tx_hash, nt.F_log_index, nt.F_token,
+				nt.F_token_id, nt.F_from, nt.F_to, NORMAL, newFormat, newFormat)
+		}
+
+		sql := "INSERT INTO t_nft_transfer (F_block, F_block_hash, F_timestamp, F_tx_hash, F_log_index, F_token, " +
+			"F_token_id, F_from, F_to, F_status, F_create_time, F_modify_time) VALUES " + strings.Join(values, ",") +
+			" ON DUPLICATE KEY UPDATE F_status = VALUES(F_status), F_modify_time = VALUES(F_modify_time)"
+
+		rdb := db.Exec(sql, args...)
+		if rdb.Error != nil {
+			log.Debugf("CreateNftTransfers error:%s", rdb.Error.Error())
+			return rdb.Error
+		}
+	}
+
+	return nil
+}
+
+// FindNftTransfersByHeight the NORMAL ERC-721 transfers of height
+func FindNftTransfersByHeight(db *gorm.DB, height int64) (transfers []NftTransfer, err error) {
+	rdb := db.Where("F_block = ? and F_status = ?", height, NORMAL).Find(&transfers)
+	if rdb.Error != nil {
+		err = errors.New("FindNftTransfersByHeight error:" + rdb.Error.Error())
+		return
+	}
+
+	return transfers, nil
+}
+
+// FindLastNftTransfer the latest NORMAL transfer of a token id, it decides the owner
+func (nt *NftTransfer) FindLastNftTransfer(db *gorm.DB, token string, tokenId string) (transfer NftTransfer, err error) {
+
+	rdb := db.Where("F_token = ? and F_token_id = ? and F_status = ?", token, tokenId, NORMAL).
+		Order("F_block desc, F_log_index desc").First(&transfer)
+	if rdb.RecordNotFound() {
+		err = errors.New(DATA_NOT_EXIST)
+	} else if rdb.Error != nil {
+		panic("FindLastNftTransfer error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return transfer, err
+}
+
+// UpdateNftTransferStatusByHeight set all NORMAL ERC-721 transfers of height to status in one statement
+func UpdateNftTransferStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_nft_transfer").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
+
+// GetNftTransfers the NORMAL transfers matching filter, newest first, and the count of all matches
+func GetNftTransfers(db *gorm.DB, filter NftFilter, offset int, size int) (transfers []NftTransfer, count int64, err error) {
+	rdb := db.Table("t_nft_transfer").Where("F_status = ?", NORMAL)
+	if filter.Token != "" {
+		rdb = rdb.Where("F_token = ?", filter.Token)
+	}
+	if filter.TokenId != "" {
+		rdb = rdb.Where("F_token_id = ?", filter.TokenId)
+	}
+	if filter.Addr != "" {
+		rdb = rdb.Where("(F_from = ? or F_to = ?)", filter.Addr, filter.Addr)
+	}
+
+	num := Count_number{}
+	cdb := rdb.Select(" count(*) as count ").Find(&num)
+	if cdb.Error != nil {
+		err = errors.New("GetNftTransfers error:" + cdb.Error.Error())
+		return
+	}
+
+	rdb = rdb.Order("F_block desc, F_log_index desc").Offset(offset).Limit(size).Find(&transfers)
+	if rdb.Error != nil {
+		err = errors.New("GetNftTransfers error:" + rdb.Error.Error())
+		return
+	}
+
+	return transfers, num.Count, nil
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"errors"
+	"github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// 链重组记录，每次回滚一行
+type Reorg struct {
+	F_id              uint64 `gorm:"column:F_id"`              //ID
+	F_ancestor        int64  `gorm:"column:F_ancestor"`        //新旧链共同祖先高度
+	F_ancestor_hash   string `gorm:"column:F_ancestor_hash"`   //共同祖先hash
+	F_depth           int64  `gorm:"column:F_depth"`           //回滚的区块数
+	F_old_head        int64  `gorm:"column:F_old_head"`        //回滚前的最高区块
+	F_old_head_hash   string `gorm:"column:F_old_head_hash"`   //回滚前的最高区块hash
+	F_new_head        int64  `gorm:"column:F_new_head"`        //发现重组的新链区块
+	F_new_head_hash   string `gorm:"column:F_new_head_hash"`   //发现重组的新链区块hash
+	F_orphaned_hashes string `gorm:"column:F_orphaned_hashes"` //回滚的区块hash，逗号分隔，从高到低
+	F_timestamp       int64  `gorm:"column:F_timestamp"`       //发现时间
+	F_create_time     string `gorm:"column:F_create_time"`     //创建时间
+	F_modify_time     string `gorm:"column:F_modify_time"`     //修改时间
+}
+
+func (r *Reorg) TableName() string {
+	return "t_reorg"
+}
+
+func (r *Reorg) BeforeCreate(scope *gorm.Scope) error {
+	currentTime := time.Now().Local()
+	newFormat := currentTime.Format("2006-01-02 15:04:05.000")
+
+	scope.SetColumn("F_create_time", newFormat)
+	scope.SetColumn("F_modify_time", newFormat)
+	return nil
+}
+
+func (r *Reorg) BeforeUpdate(scope *gorm.Scope) error {
+	currentTime := time.Now().Local()
+	newFormat := currentTime.Format("2006-01-02 15:04:05.000")
+	scope.SetColumn("F_modify_time", newFormat)
+	return nil
+}
+
+func (r *Reorg) CreateReorg(db *gorm.DB) (err error) {
+
+	log.Debugf("CreateReorg,ancestor:%d,depth:%d,old_head:%d,new_head:%d", r.F_ancestor, r.F_depth, r.F_old_head, r.F_new_head)
+	util.ASSERT(r.F_depth > 0, "CreateReorg, F_depth must be positive")
+
+	rdb := db.Create(&r)
+
+	return rdb.Error
+}
+
+// OrphanedHashes split F_orphaned_hashes
+func (r *Reorg) OrphanedHashes() []string {
+	if r.F_orphaned_hashes == "" {
+		return []string{}
+	}
+	return strings.Split(r.F_orphaned_hashes, ",")
+}
+
+func GetRecentReorgs(db *gorm.DB, offset int, size int) (reorgs []Reorg, err error) {
+	rdb := db.Order("F_id desc").Offset(offset).Limit(size).Find(&reorgs)
+	if rdb.Error != nil {
+		err = errors.New("GetRecentReorgs error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return reorgs, err
+}
+
+func GetReorgNum(db *gorm.DB) (count int64, err error) {
+	num := Count_number{}
+	rdb := db.Table("t_reorg").Select(" count(*) as count ").Find(&num)
+	if rdb.Error != nil {
+		err = errors.New("GetReorgNum error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+	return num.Count, err
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"time"
+)
+
+// 出块收益流水，只追加不修改。区块写入时记一条正的，分叉时记一条负的冲正，
+// 同一区块的流水按F_seq递增，偶数是入账，奇数是冲正
+type RewardEntry struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_block       int64  `gorm:"column:F_block"`
+	F_block_hash  string `gorm:"column:F_block_hash"`
+	F_seq         int64  `gorm:"column:F_seq"`
+	F_miner       string `gorm:"column:F_miner"`
+	F_timestamp   int64  `gorm:"column:F_timestamp"`
+	F_sign        int    `gorm:"column:F_sign"`        //1 入账，-1 冲正
+	F_reward      string `gorm:"column:F_reward"`      //decimal(65,0)，冲正为负
+	F_fees        string `gorm:"column:F_fees"`        //decimal(65,0)，冲正为负
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+}
+
+// RewardTotal is the reward and fees of a miner summed from the ledger
+type RewardTotal struct {
+	F_miner string `gorm:"column:F_miner"`
+	Reward  string `gorm:"column:reward"`
+	Fees    string `gorm:"column:fees"`
+}
+
+func (e *RewardEntry) TableName() string {
+	return "t_reward_ledger"
+}
+
+const rewardTotalColumns = "CAST(COALESCE(SUM(F_reward), 0) AS CHAR) as reward, CAST(COALESCE(SUM(F_fees), 0) AS CHAR) as fees"
+
+// CreateRewardEntry append an entry, the unique (F_block_hash, F_seq) fails the second writer of the same entry
+func CreateRewardEntry(db *gorm.DB, entry RewardEntry) (err error) {
+	ASSERT(entry.F_block_hash != "", "CreateRewardEntry, F_block_hash can't be nul")
+	ASSERT(entry.F_sign == 1 || entry.F_sign == -1, "CreateRewardEntry, F_sign must be 1 or -1")
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	sql := "INSERT INTO t_reward_ledger (F_block, F_block_hash, F_seq, F_miner, F_timestamp, F_sign, F_reward, F_fees, " +
+		"F_create_time) VALUES (?,?,?,?,?,?,?,?,?)"
+
+	rdb := db.Exec(sql, entry.F_block, entry.F_block_hash, entry.F_seq, entry.F_miner, entry.F_timestamp, entry.F_sign,
+		entry.F_reward, entry.F_fees, newFormat)
+	if rdb.Error != nil {
+		log.Debugf("CreateRewardEntry error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// FindLastRewardEntry the latest entry of a block hash, the block is credited when its sign is 1
+func FindLastRewardEntry(db *gorm.DB, hash string) (entry RewardEntry, err error) {
+
+	rdb := db.Where("F_block_hash = ?", hash).Order("F_seq desc").First(&entry)
+	if rdb.RecordNotFound() {
+		err = errors.New(DATA_NOT_EXIST)
+	} else if rdb.Error != nil {
+		panic("FindLastRewardEntry error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return entry, err
+}
+
+// GetRewardEntriesByHeight the entries of heights [from, to], in F_seq order of each block hash
+func GetRewardEntriesByHeight(db *gorm.DB, from int64, to int64) (entries []RewardEntry, err error) {
+	rdb := db.Where("F_block >= ? and F_block <= ?", from, to).Order("F_block, F_block_hash, F_seq").Find(&entries)
+	if rdb.Error != nil {
+		err = errors.New("GetRewardEntriesByHeight error:" + rdb.Error.Error())
+	}
+
+	return entries, err
+}
+
+// GetRewardTotals the reward and fees of each miner in the ledger
+func GetRewardTotals(db *gorm.DB) (totals []RewardTotal, err error) {
+	rdb := db.Table("t_reward_ledger").Select("F_miner, " + rewardTotalColumns).Group("F_miner").Scan(&totals)
+	if rdb.Error != nil {
+		err = errors.New("GetRewardTotals error:" + rdb.Error.Error())
+	}
+
+	return totals, err
+}
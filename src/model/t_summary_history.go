@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"time"
+)
+
+// 全网概况的快照，每SnapshotInterval个区块一条
+type SummaryHistory struct {
+	F_id             uint64 `gorm:"column:F_id"` //ID
+	F_block          int64  `gorm:"column:F_block"`
+	F_block_hash     string `gorm:"column:F_block_hash"`
+	F_timestamp      int64  `gorm:"column:F_timestamp"`
+	F_difficulty     string `gorm:"column:F_difficulty"`     //平均难度
+	F_capacity       string `gorm:"column:F_capacity"`       //全网容量(byte)
+	F_online_miners  int64  `gorm:"column:F_online_miners"`  //在线矿工数
+	F_block_count    int64  `gorm:"column:F_block_count"`    //24小时爆块数
+	F_day_reward     string `gorm:"column:F_day_reward"`     //24小时奖励(wei)
+	F_pb_day_reward  string `gorm:"column:F_pb_day_reward"`  //每PB日均收益(wei)
+	F_total_rewarded string `gorm:"column:F_total_rewarded"` //总已挖数量(wei)，空为未取到
+	F_total_mortgage string `gorm:"column:F_total_mortgage"` //总抵押数量(wei)，空为未取到
+	F_status         int    `gorm:"column:F_status"`         //0 非法 ，1正常，2分叉
+	F_create_time    string `gorm:"column:F_create_time"`    //创建时间
+	F_modify_time    string `gorm:"column:F_modify_time"`    //修改时间
+}
+
+func (h *SummaryHistory) TableName() string {
+	return "t_summary_history"
+}
+
+// CreateSummaryHistory write the snapshot of a block, the snapshot of a block hash taken again replaces the old one
+func CreateSummaryHistory(db *gorm.DB, h SummaryHistory) (err error) {
+	ASSERT(h.F_block_hash != "", "CreateSummaryHistory, F_block_hash can't be nul")
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	sql := "INSERT INTO t_summary_history (F_block, F_block_hash, F_timestamp, F_difficulty, F_capacity, F_online_miners, " +
+		"F_block_count, F_day_reward, F_pb_day_reward, F_total_rewarded, F_total_mortgage, F_status, F_create_time, " +
+		"F_modify_time) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)" +
+		" ON DUPLICATE KEY UPDATE F_difficulty = VALUES(F_difficulty), F_capacity = VALUES(F_capacity), " +
+		"F_online_miners = VALUES(F_online_miners), F_block_count = VALUES(F_block_count), " +
+		"F_day_reward = VALUES(F_day_reward), F_pb_day_reward = VALUES(F_pb_day_reward), " +
+		"F_total_rewarded = VALUES(F_total_rewarded), F_total_mortgage = VALUES(F_total_mortgage), " +
+		"F_status = VALUES(F_status), F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, h.F_block, h.F_block_hash, h.F_timestamp, h.F_difficulty, h.F_capacity, h.F_online_miners,
+		h.F_block_count, h.F_day_reward, h.F_pb_day_reward, h.F_total_rewarded, h.F_total_mortgage, NORMAL,
+		newFormat, newFormat)
+	if rdb.Error != nil {
+		log.Debugf("CreateSummaryHistory error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// UpdateSummaryHistoryStatusByHeight set the NORMAL snapshot of height to status
+func UpdateSummaryHistoryStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_summary_history").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
+
+func (h *SummaryHistory) FindSummaryHistoryByHash(db *gorm.DB, hash string) (history SummaryHistory, err error) {
+
+	rdb := db.Where("F_block_hash = ? and F_status = ?", hash, NORMAL).First(&history)
+	if rdb.RecordNotFound() {
+		err = errors.New(DATA_NOT_EXIST)
+	} else if rdb.Error != nil {
+		panic("FindSummaryHistoryByHash error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return history, err
+}
+
+// GetSummaryHistory the NORMAL snapshots taken in [begin, end), oldest first
+func GetSummaryHistory(db *gorm.DB, begin int64, end int64) (history []SummaryHistory, err error) {
+	rdb := db.Where("F_timestamp >= ? and F_timestamp < ? and F_status = ?", begin, end, NORMAL).
+		Order("F_block").Find(&history)
+	if rdb.Error != nil {
+		err = errors.New("GetSummaryHistory error:" + rdb.Error.Error())
+	}
+
+	return history, err
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"time"
+)
+
+// SYNC_STATE_LIVE is the state row of the syncer following the chain head
+const SYNC_STATE_LIVE = "live"
+
+// 同步进度，每个同步任务一行
+type SyncState struct {
+	F_id              uint64 `gorm:"column:F_id"` //ID
+	F_name            string `gorm:"column:F_name"`
+	F_height          int64  `gorm:"column:F_height"`          //最后提交的区块高度
+	F_hash            string `gorm:"column:F_hash"`            //最后提交的区块hash
+	F_head            int64  `gorm:"column:F_head"`            //最后看到的链上高度
+	F_head_time       int64  `gorm:"column:F_head_time"`       //最后看到链上高度的时间
+	F_reorg_count     int64  `gorm:"column:F_reorg_count"`     //分叉回退次数
+	F_last_reorg_time int64  `gorm:"column:F_last_reorg_time"` //最后一次分叉回退时间
+	F_error_count     int64  `gorm:"column:F_error_count"`     //同步出错次数
+	F_last_error      string `gorm:"column:F_last_error"`      //最后一次错误
+	F_last_error_time int64  `gorm:"column:F_last_error_time"` //最后一次错误时间
+	F_create_time     string `gorm:"column:F_create_time"`     //创建时间
+	F_modify_time     string `gorm:"column:F_modify_time"`     //修改时间
+}
+
+func (s *SyncState) TableName() string {
+	return "t_sync_state"
+}
+
+func (s *SyncState) FindSyncState(db *gorm.DB, name string) (state SyncState, err error) {
+
+	rdb := db.Where("F_name = ?", name).First(&state)
+	if rdb.RecordNotFound() {
+		err = errors.New(DATA_NOT_EXIST)
+	} else if rdb.Error != nil {
+		panic("FindSyncState error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return state, err
+}
+
+// UpdateSyncHeight record the last committed block, call it in the transaction writing the block
+func UpdateSyncHeight(db *gorm.DB, name string, height int64, hash string) (err error) {
+	return upsertSyncState(db, name, "F_height = VALUES(F_height), F_hash = VALUES(F_hash)",
+		map[string]interface{}{"F_height": height, "F_hash": hash})
+}
+
+// UpdateSyncHead record the chain head last seen
+func UpdateSyncHead(db *gorm.DB, name string, head int64) (err error) {
+	return upsertSyncState(db, name, "F_head = VALUES(F_head), F_head_time = VALUES(F_head_time)",
+		map[string]interface{}{"F_head": head, "F_head_time": time.Now().Unix()})
+}
+
+// AddSyncReorg count one reorg
+func AddSyncReorg(db *gorm.DB, name string) (err error) {
+	return upsertSyncState(db, name, "F_reorg_count = F_reorg_count + 1, F_last_reorg_time = VALUES(F_last_reorg_time)",
+		map[string]interface{}{"F_reorg_count": 1, "F_last_reorg_time": time.Now().Unix()})
+}
+
+// AddSyncError count one error and keep its message
+func AddSyncError(db *gorm.DB, name string, syncerr error) (err error) {
+	msg := syncerr.Error()
+	if len(msg) > 512 {
+		msg = msg[:512]
+	}
+
+	return upsertSyncState(db, name,
+		"F_error_count = F_error_count + 1, F_last_error = VALUES(F_last_error), F_last_error_time = VALUES(F_last_error_time)",
+		map[string]interface{}{"F_error_count": 1, "F_last_error": msg, "F_last_error_time": time.Now().Unix()})
+}
+
+// DeleteSyncState forget the state of name
+func DeleteSyncState(db *gorm.DB, name string) (err error) {
+	rdb := db.Where("F_name = ?", name).Delete(&SyncState{})
+	return rdb.Error
+}
+
+// upsertSyncState insert the row of name with columns, or run update on the existing one
+func upsertSyncState(db *gorm.DB, name string, update string, columns map[string]interface{}) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+
+	names := "F_name, F_create_time, F_modify_time"
+	values := "?, ?, ?"
+	args := []interface{}{name, newFormat, newFormat}
+	for column, value := range columns {
+		names += ", " + column
+		values += ", ?"
+		args = append(args, value)
+	}
+
+	sql := "INSERT INTO t_sync_state (" + names + ") VALUES (" + values + ")" +
+		" ON DUPLICATE KEY UPDATE " + update + ", F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, args...)
+	if rdb.Error != nil {
+		log.Debugf("upsertSyncState:%s error:%s", name, rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
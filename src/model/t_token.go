@@ -0,0 +1,134 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// 代币合约
+type Token struct {
+	F_id           uint64 `gorm:"column:F_id"` //ID
+	F_address      string `gorm:"column:F_address"`
+	F_type         string `gorm:"column:F_type"` //erc20
+	F_name         string `gorm:"column:F_name"`
+	F_symbol       string `gorm:"column:F_symbol"`
+	F_decimals     int    `gorm:"column:F_decimals"`     //-1 未知
+	F_total_supply string `gorm:"column:F_total_supply"` //空为未知
+	F_supply_block int64  `gorm:"column:F_supply_block"` //F_total_supply查询的高度，-1 未知
+	F_block        int64  `gorm:"column:F_block"`        //最早的转账高度
+	F_create_time  string `gorm:"column:F_create_time"`  //创建时间
+	F_modify_time  string `gorm:"column:F_modify_time"`  //修改时间
+}
+
+func (t *Token) TableName() string {
+	return "t_token"
+}
+
+// CreateTokens write the tokens seen in a block, the metadata is only updated with the known fields,
+// a token without metadata (F_decimals -1, empty name...) keeps what is in databases, and a totalSupply
+// older than the stored one, written by backfill, is ignored
+func CreateTokens(db *gorm.DB, tokens []Token) (err error) {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	values := make([]string, 0, len(tokens))
+	args := make([]interface{}, 0, len(tokens)*10)
+	for _, t := range tokens {
+		ASSERT(t.F_address != "", "CreateTokens, F_address can't be nul")
+
+		values = append(values, "(?,?,?,?,?,?,?,?,?,?)")
+		args = append(args, t.F_address, t.F_type, t.F_name, t.F_symbol, t.F_decimals, t.F_total_supply, t.F_supply_block,
+			t.F_block, newFormat, newFormat)
+	}
+
+	sql := "INSERT INTO t_token (F_address, F_type, F_name, F_symbol, F_decimals, F_total_supply, F_supply_block, F_block, " +
+		"F_create_time, F_modify_time) VALUES " + strings.Join(values, ",") +
+		" ON DUPLICATE KEY UPDATE " +
+		"F_name = IF(VALUES(F_name) = '', F_name, VALUES(F_name)), " +
+		"F_symbol = IF(VALUES(F_symbol) = '', F_symbol, VALUES(F_symbol)), " +
+		"F_decimals = IF(VALUES(F_decimals) < 0, F_decimals, VALUES(F_decimals)), " +
+		"F_total_supply = IF(VALUES(F_total_supply) = '' or VALUES(F_supply_block) < F_supply_block, F_total_supply, VALUES(F_total_supply)), " +
+		"F_supply_block = IF(VALUES(F_total_supply) = '' or VALUES(F_supply_block) < F_supply_block, F_supply_block, VALUES(F_supply_block)), " +
+		"F_block = IF(F_block < 0, VALUES(F_block), LEAST(F_block, VALUES(F_block))), " +
+		"F_modify_time = VALUES(F_modify_time)"
+
+	rdb := db.Exec(sql, args...)
+	if rdb.Error != nil {
+		log.Debugf("CreateTokens error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+func (t *Token) FindTokenByAddr(db *gorm.DB, addr string) (token Token, err error) {
+
+	rdb := db.Where("F_address = ?", strings.ToLower(addr)).First(&token)
+	if rdb.RecordNotFound() {
+		err = errors.New(DATA_NOT_EXIST)
+	} else if rdb.Error != nil {
+		panic("FindTokenByAddr error:" + rdb.Error.Error())
+	} else {
+		err = nil
+	}
+
+	return token, err
+}
+
+// GetTokensByAddrs the tokens of addrs keyed by address, unknown ones are left out
+func GetTokensByAddrs(db *gorm.DB, addrs []string) (tokens map[string]Token, err error) {
+	tokens = make(map[string]Token)
+	if len(addrs) == 0 {
+		return tokens, nil
+	}
+
+	list := []Token{}
+	rdb := db.Where("F_address in (?)", addrs).Find(&list)
+	if rdb.Error != nil {
+		return nil, errors.New("GetTokensByAddrs error:" + rdb.Error.Error())
+	}
+	for _, t := range list {
+		tokens[t.F_address] = t
+	}
+
+	return tokens, nil
+}
+
+// SearchTokens the tokens whose symbol or name starts with prefix, shortest symbol first
+func SearchTokens(db *gorm.DB, prefix string, size int) (tokens []Token, err error) {
+	pattern := likePrefix(prefix)
+	rdb := db.Where("F_symbol LIKE ? or F_name LIKE ?", pattern, pattern).Order("LENGTH(F_symbol), F_symbol, F_address").
+		Limit(size).Find(&tokens)
+	if rdb.Error != nil {
+		err = errors.New("SearchTokens error:" + rdb.Error.Error())
+	}
+
+	return tokens, err
+}
+
+func GetTokens(db *gorm.DB, tokenType string, offset int, size int) (tokens []Token, err error) {
+	rdb := db.Where("F_type = ?", tokenType).Order("F_id asc").Offset(offset).Limit(size).Find(&tokens)
+	if rdb.Error != nil {
+		err = errors.New("GetTokens error:" + rdb.Error.Error())
+		return
+	}
+
+	return tokens, nil
+}
+
+func GetTokenNum(db *gorm.DB, tokenType string) (count int64, err error) {
+	num := Count_number{}
+	rdb := db.Table("t_token").Where("F_type = ?", tokenType).Select(" count(*) as count ").Find(&num)
+	if rdb.Error != nil {
+		err = errors.New("GetTokenNum error:" + rdb.Error.Error())
+		return
+	}
+
+	return num.Count, nil
+}
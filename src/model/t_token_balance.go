@@ -0,0 +1,115 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// 代币持有者余额，由转账累加，分叉时减回
+type TokenBalance struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_token       string `gorm:"column:F_token"`
+	F_holder      string `gorm:"column:F_holder"`
+	F_balance     string `gorm:"column:F_balance"`     //decimal(65,0)
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+type TokenHolderCount struct {
+	F_token string `gorm:"column:F_token"`
+	Count   int64  `gorm:"column:count"`
+}
+
+func (tb *TokenBalance) TableName() string {
+	return "t_token_balance"
+}
+
+// AddTokenBalances add the F_balance of each row (may be negative) to the holder's balance,
+// callers sort the rows so concurrent blocks lock them in the same order
+func AddTokenBalances(db *gorm.DB, deltas []TokenBalance) (err error) {
+	const rowsPerInsert = 500
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	for start := 0; start < len(deltas); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(deltas) {
+			end = len(deltas)
+		}
+
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*5)
+		for _, tb := range deltas[start:end] {
+			ASSERT(tb.F_token != "" && tb.F_holder != "", "AddTokenBalances, F_token and F_holder can't be nul")
+
+			values = append(values, "(?,?,?,?,?)")
+			args = append(args, tb.F_token, tb.F_holder, tb.F_balance, newFormat, newFormat)
+		}
+
+		sql := "INSERT INTO t_token_balance (F_token, F_holder, F_balance, F_create_time, F_modify_time) VALUES " +
+			strings.Join(values, ",") +
+			" ON DUPLICATE KEY UPDATE F_balance = F_balance + VALUES(F_balance), F_modify_time = VALUES(F_modify_time)"
+
+		rdb := db.Exec(sql, args...)
+		if rdb.Error != nil {
+			log.Debugf("AddTokenBalances error:%s", rdb.Error.Error())
+			return rdb.Error
+		}
+	}
+
+	return nil
+}
+
+// GetTokenHolders the holders of token with a positive balance, largest first, and their count
+func GetTokenHolders(db *gorm.DB, token string, offset int, size int) (holders []TokenBalance, count int64, err error) {
+	rdb := db.Table("t_token_balance").Where("F_token = ? and F_balance > 0", token)
+
+	num := Count_number{}
+	cdb := rdb.Select(" count(*) as count ").Find(&num)
+	if cdb.Error != nil {
+		err = errors.New("GetTokenHolders error:" + cdb.Error.Error())
+		return
+	}
+
+	rdb = rdb.Order("F_balance desc").Offset(offset).Limit(size).Find(&holders)
+	if rdb.Error != nil {
+		err = errors.New("GetTokenHolders error:" + rdb.Error.Error())
+		return
+	}
+
+	return holders, num.Count, nil
+}
+
+// GetTokenHolderCounts the holders with a positive balance of each token, keyed by token
+func GetTokenHolderCounts(db *gorm.DB, tokens []string) (counts map[string]int64, err error) {
+	counts = make(map[string]int64)
+	if len(tokens) == 0 {
+		return counts, nil
+	}
+
+	list := []TokenHolderCount{}
+	rdb := db.Table("t_token_balance").Where("F_token in (?) and F_balance > 0", tokens).
+		Select(" F_token, count(*) as count ").Group("F_token").Scan(&list)
+	if rdb.Error != nil {
+		return nil, errors.New("GetTokenHolderCounts error:" + rdb.Error.Error())
+	}
+	for _, c := range list {
+		counts[c.F_token] = c.Count
+	}
+
+	return counts, nil
+}
+
+// GetTokenBalancesByAddr the non zero token balances of addr
+func GetTokenBalancesByAddr(db *gorm.DB, addr string) (balances []TokenBalance, err error) {
+	rdb := db.Where("F_holder = ? and F_balance <> 0", addr).Order("F_token asc").Find(&balances)
+	if rdb.Error != nil {
+		err = errors.New("GetTokenBalancesByAddr error:" + rdb.Error.Error())
+		return
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"errors"
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// 代币转账，由Transfer日志解析
+type TokenTransfer struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_block       int64  `gorm:"column:F_block"`
+	F_block_hash  string `gorm:"column:F_block_hash"`
+	F_timestamp   int64  `gorm:"column:F_timestamp"`
+	F_tx_hash     string `gorm:"column:F_tx_hash"`
+	F_log_index   int64  `gorm:"column:F_log_index"` //区块内日志序号
+	F_token       string `gorm:"column:F_token"`     //代币合约
+	F_from        string `gorm:"column:F_from"`
+	F_to          string `gorm:"column:F_to"`
+	F_value       string `gorm:"column:F_value"`
+	F_status      int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+func (tt *TokenTransfer) TableName() string {
+	return "t_token_transfer"
+}
+
+// CreateTokenTransfers write all token transfers of a block with multi-row inserts,
+// transfers of a restored block are set NORMAL again
+func CreateTokenTransfers(db *gorm.DB, transfers []TokenTransfer) (err error) {
+	const rowsPerInsert = 500
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	for start := 0; start < len(transfers); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(transfers) {
+			end = len(transfers)
+		}
+
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*12)
+		for _, tt := range transfers[start:end] {
+			ASSERT(tt.F_block_hash != "", "CreateTokenTransfers, F_block_hash can't be nul")
+
+			values = append(values, "(?,?,?,?,?,?,?,?,?,?,?,?)")
+			args = append(args, tt.F_block, tt.F_block_hash, tt.F_timestamp, tt.F_tx_hash, tt.F_log_index, tt.F_token,
+				tt.F_from, tt.F_to, tt.F_value, NORMAL, newFormat, newFormat)
+		}
+
+		sql := "INSERT INTO t_token_transfer (F_block, F_block_hash, F_timestamp, F_tx_hash, F_log_index, F_token, " +
+			"F_from, F_to, F_value, F_status, F_create_time, F_modify_time) VALUES " + strings.Join(values, ",") +
+			" ON DUPLICATE KEY UPDATE F_status = VALUES(F_status), F_modify_time = VALUES(F_modify_time)"
+
+		rdb := db.Exec(sql, args...)
+		if rdb.Error != nil {
+			log.Debugf("CreateTokenTransfers error:%s", rdb.Error.Error())
+			return rdb.Error
+		}
+	}
+
+	return nil
+}
+
+// FindTokenTransfersByHeight the NORMAL token transfers of height
+func FindTokenTransfersByHeight(db *gorm.DB, height int64) (transfers []TokenTransfer, err error) {
+	rdb := db.Where("F_block = ? and F_status = ?", height, NORMAL).Find(&transfers)
+	if rdb.Error != nil {
+		err = errors.New("FindTokenTransfersByHeight error:" + rdb.Error.Error())
+		return
+	}
+
+	return transfers, nil
+}
+
+// UpdateTokenTransferStatusByHeight set all NORMAL token transfers of height to status in one statement
+func UpdateTokenTransferStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_token_transfer").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
+
+// GetTokenTransfers the NORMAL transfers of token and/or addr (empty for any), newest first, and the count of all matches
+func GetTokenTransfers(db *gorm.DB, token string, addr string, offset int, size int) (transfers []TokenTransfer, count int64, err error) {
+	rdb := db.Table("t_token_transfer").Where("F_status = ?", NORMAL)
+	if token != "" {
+		rdb = rdb.Where("F_token = ?", token)
+	}
+	if addr != "" {
+		rdb = rdb.Where("(F_from = ? or F_to = ?)", addr, addr)
+	}
+
+	num := Count_number{}
+	cdb := rdb.Select(" count(*) as count ").Find(&num)
+	if cdb.Error != nil {
+		err = errors.New("GetTokenTransfers error:" + cdb.Error.Error())
+		return
+	}
+
+	rdb = rdb.Order("F_block desc, F_log_index desc").Offset(offset).Limit(size).Find(&transfers)
+	if rdb.Error != nil {
+		err = errors.New("GetTokenTransfers error:" + rdb.Error.Error())
+		return
+	}
+
+	return transfers, num.Count, nil
+}
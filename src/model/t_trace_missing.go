@@ -0,0 +1,67 @@
+package model
+
+import (
+	. "github.com/EthereumHD/Scan/src/const"
+	. "github.com/EthereumHD/Scan/src/util"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// 追踪失败的合约交易（超时、状态已裁剪等），区块照常写入，内部交易缺失，重新backfill该高度可补上
+type TraceMissing struct {
+	F_id          uint64 `gorm:"column:F_id"` //ID
+	F_block       int64  `gorm:"column:F_block"`
+	F_block_hash  string `gorm:"column:F_block_hash"`
+	F_tx_hash     string `gorm:"column:F_tx_hash"`
+	F_error       string `gorm:"column:F_error"`
+	F_status      int    `gorm:"column:F_status"`      //0 非法 ，1正常，2分叉
+	F_create_time string `gorm:"column:F_create_time"` //创建时间
+	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+}
+
+func (m *TraceMissing) TableName() string {
+	return "t_trace_missing"
+}
+
+// ReplaceTraceMissings set the missing traces of a block hash to the ones of the last sync
+func ReplaceTraceMissings(db *gorm.DB, hash string, missings []TraceMissing) (err error) {
+	rdb := db.Exec("DELETE FROM t_trace_missing WHERE F_block_hash = ?", hash)
+	if rdb.Error != nil {
+		log.Debugf("ReplaceTraceMissings error:%s", rdb.Error.Error())
+		return rdb.Error
+	}
+	if len(missings) == 0 {
+		return nil
+	}
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	values := make([]string, 0, len(missings))
+	args := make([]interface{}, 0, len(missings)*7)
+	for _, m := range missings {
+		ASSERT(m.F_block_hash == hash, "ReplaceTraceMissings, F_block_hash must be the replaced hash")
+
+		values = append(values, "(?,?,?,?,?,?,?)")
+		args = append(args, m.F_block, m.F_block_hash, m.F_tx_hash, m.F_error, NORMAL, newFormat, newFormat)
+	}
+
+	sql := "INSERT INTO t_trace_missing (F_block, F_block_hash, F_tx_hash, F_error, F_status, F_create_time, F_modify_time) " +
+		"VALUES " + strings.Join(values, ",")
+
+	rdb = db.Exec(sql, args...)
+	if rdb.Error != nil {
+		log.Debugf("ReplaceTraceMissings error:%s", rdb.Error.Error())
+	}
+
+	return rdb.Error
+}
+
+// UpdateTraceMissingStatusByHeight set all NORMAL missing traces of height to status in one statement
+func UpdateTraceMissingStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_trace_missing").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
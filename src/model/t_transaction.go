@@ -7,23 +7,32 @@ import (
 	_ "fmt"
 	"github.com/jinzhu/gorm"
 	"qoobing.com/utillib.golang/log"
+	"strings"
 	"time"
 )
 
 type Transaction struct {
-	F_id          uint64 `gorm:"column:F_id"` //ID
-	F_tx_hash     string `gorm:"column:F_tx_hash"`
-	F_block       int64  `gorm:"column:F_block"`
-	F_timestamp   int64  `gorm:"column:F_timestamp"`
-	F_from        string `gorm:"column:F_from"`
-	F_to          string `gorm:"column:F_to"`
-	F_value       string `gorm:"column:F_value"`
-	F_tx_fee      string `gorm:"column:F_tx_fee"`
-	F_status      int    `gorm:"column:F_status"` //0 非法 ，1正常，2分叉
-	F_tx_type     int64  `gorm:"column:F_tx_type"`
-	F_tx_type_ext string `gorm:"column:F_tx_type_ext"`
-	F_create_time string `gorm:"column:F_create_time"` //创建时间
-	F_modify_time string `gorm:"column:F_modify_time"` //修改时间
+	F_id               uint64 `gorm:"column:F_id"` //ID
+	F_tx_hash          string `gorm:"column:F_tx_hash"`
+	F_block            int64  `gorm:"column:F_block"`
+	F_timestamp        int64  `gorm:"column:F_timestamp"`
+	F_from             string `gorm:"column:F_from"`
+	F_to               string `gorm:"column:F_to"`
+	F_value            string `gorm:"column:F_value"`
+	F_tx_fee           string `gorm:"column:F_tx_fee"`
+	F_status           int    `gorm:"column:F_status"` //0 非法 ，1正常，2分叉
+	F_tx_type          int64  `gorm:"column:F_tx_type"`
+	F_tx_type_ext      string `gorm:"column:F_tx_type_ext"`
+	F_nonce            int64  `gorm:"column:F_nonce"`
+	F_tx_index         int64  `gorm:"column:F_tx_index"` //区块内序号
+	F_gas              string `gorm:"column:F_gas"`      //gas limit
+	F_gas_price        string `gorm:"column:F_gas_price"`
+	F_gas_used         string `gorm:"column:F_gas_used"`
+	F_input            string `gorm:"column:F_input"`
+	F_receipt_status   int    `gorm:"column:F_receipt_status"`   //-1 未知，0失败，1成功
+	F_contract_address string `gorm:"column:F_contract_address"` //创建的合约地址
+	F_create_time      string `gorm:"column:F_create_time"`      //创建时间
+	F_modify_time      string `gorm:"column:F_modify_time"`      //修改时间
 
 }
 
@@ -69,6 +78,48 @@ func (t *Transaction) CreateTransaction(db *gorm.DB) (err error) {
 	return rdb.Error
 }
 
+//CreateTransactions write all transcations with multi-row inserts.
+//A transcation already in databases, e.g. forked before, is moved to its new block and set NORMAL
+func CreateTransactions(db *gorm.DB, transactions []Transaction) (err error) {
+	const rowsPerInsert = 500
+
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	for start := 0; start < len(transactions); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(transactions) {
+			end = len(transactions)
+		}
+
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*20)
+		for _, t := range transactions[start:end] {
+			ASSERT(t.F_tx_hash != "", "CreateTransactions, F_tx_hash can't be nul")
+
+			values = append(values, "(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
+			args = append(args, t.F_tx_hash, t.F_block, t.F_timestamp, t.F_from, t.F_to, t.F_value, t.F_tx_fee,
+				NORMAL, t.F_tx_type, t.F_tx_type_ext, t.F_nonce, t.F_tx_index, t.F_gas, t.F_gas_price, t.F_gas_used,
+				t.F_input, t.F_receipt_status, t.F_contract_address, newFormat, newFormat)
+		}
+
+		sql := "INSERT INTO t_transaction (F_tx_hash, F_block, F_timestamp, F_from, F_to, F_value, F_tx_fee, " +
+			"F_status, F_tx_type, F_tx_type_ext, F_nonce, F_tx_index, F_gas, F_gas_price, F_gas_used, " +
+			"F_input, F_receipt_status, F_contract_address, F_create_time, F_modify_time) VALUES " + strings.Join(values, ",") +
+			" ON DUPLICATE KEY UPDATE F_block = VALUES(F_block), F_timestamp = VALUES(F_timestamp), " +
+			"F_tx_fee = VALUES(F_tx_fee), F_status = VALUES(F_status), F_tx_index = VALUES(F_tx_index), " +
+			"F_nonce = VALUES(F_nonce), F_gas = VALUES(F_gas), F_gas_price = VALUES(F_gas_price), " +
+			"F_gas_used = VALUES(F_gas_used), F_input = VALUES(F_input), F_receipt_status = VALUES(F_receipt_status), " +
+			"F_contract_address = VALUES(F_contract_address), F_modify_time = VALUES(F_modify_time)"
+
+		rdb := db.Exec(sql, args...)
+		if rdb.Error != nil {
+			log.Debugf("CreateTransactions error:%s", rdb.Error.Error())
+			return rdb.Error
+		}
+	}
+
+	return nil
+}
+
 func (t *Transaction) FindTrasactionByHash(db *gorm.DB, hash string) (transcation Transaction, err error) {
 
 	rdb := db.Where("F_tx_hash = ?", hash).First(&transcation)
@@ -97,6 +148,20 @@ func (t *Transaction) FindTrasactionByHeight(db *gorm.DB, height int64) (transca
 	return transcations, err
 }
 
+//HasDetail whether the row was written with the detail columns, rows synced by older versions have none
+func (t *Transaction) HasDetail() bool {
+	return t.F_gas_price != ""
+}
+
+//UpdateTransactionStatusByHeight set all NORMAL transcations of height to status in one statement
+func UpdateTransactionStatusByHeight(db *gorm.DB, height int64, status int) (err error) {
+	newFormat := time.Now().Local().Format("2006-01-02 15:04:05.000")
+	rdb := db.Table("t_transaction").Where("F_block = ? and F_status = ?", height, NORMAL).
+		Updates(map[string]interface{}{"F_status": status, "F_modify_time": newFormat})
+
+	return rdb.Error
+}
+
 func (t *Transaction) UpdateTransactionStatus(db *gorm.DB) (err error) {
 	updateinfo := map[string]interface{}{"F_status": t.F_status}
 	return t.updateTransactionColumn(db, updateinfo)
@@ -106,16 +171,9 @@ func (t *Transaction) updateTransactionColumn(db *gorm.DB, updateinfo map[string
 
 	log.Debugf("updateTransactionColumn F_id:%d,block:%d,%+v", t.F_id, t.F_block, updateinfo)
 
-	tx := db.Begin()
-
-	rdb := tx.Where("F_id = ?", t.F_id).Model(&t).Update(updateinfo)
-
-	if rdb.Error != nil {
-		tx.Rollback()
-		return rdb.Error
-	}
+	//single statement, db may be a transaction of the caller
+	rdb := db.Where("F_id = ?", t.F_id).Model(&t).Update(updateinfo)
 
-	tx.Commit()
 	return rdb.Error
 }
 
@@ -0,0 +1,196 @@
+package sync
+
+import (
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+	gosync "sync"
+	"time"
+)
+
+// BACKFILL_RETRY is how many times a height is tried before its chunk is given up
+const BACKFILL_RETRY = 5
+
+// Backfill re-index heights [from, to] with workers fetching chunks in parallel.
+// Chunks are aligned to multiples of chunk and each keeps its progress in t_sync_state,
+// so an interrupted backfill resumes where it stopped, reset forgets that progress first.
+// Heights above the live syncer checkpoint belong to the live syncer and are skipped, a negative to
+// backfills up to that checkpoint. The daily stats are rebuilt from the day of from on at the end
+func Backfill(from, to int64, workers int, chunk int64, reset bool) error {
+	if workers <= 0 {
+		workers = 1
+	}
+	if chunk <= 0 {
+		chunk = 1000
+	}
+	if from < 0 {
+		from = 0
+	}
+
+	//make sure the lazy connections are created before workers share them
+	c.Web3()
+	c.Mysql()
+	summarizeStats = false
+
+	//1.leave the head to the live syncer
+	state, err := (&model.SyncState{}).FindSyncState(c.Mysql(), model.SYNC_STATE_LIVE)
+	if to < 0 {
+		if err != nil {
+			return fmt.Errorf("live syncer height unknown,give --to:%s", err.Error())
+		}
+		to = state.F_height
+	}
+	if err == nil && to > state.F_height {
+		log.Noticef("Backfill to:%d above live syncer height:%d,backfill to %d", to, state.F_height, state.F_height)
+		to = state.F_height
+	}
+	if to < from {
+		log.Noticef("Backfill nothing to do,from:%d,to:%d", from, to)
+		return nil
+	}
+
+	//2.split into aligned chunks
+	type chunkRange struct {
+		name       string
+		start, end int64
+	}
+	chunks := make(chan chunkRange)
+	go func() {
+		defer close(chunks)
+		for k := from / chunk; k*chunk <= to; k++ {
+			start, end := k*chunk, (k+1)*chunk-1
+			if start < from {
+				start = from
+			}
+			if end > to {
+				end = to
+			}
+			chunks <- chunkRange{name: fmt.Sprintf("backfill_%d", k*chunk), start: start, end: end}
+		}
+	}()
+
+	//3.workers, each one syncs its chunk in height order
+	var (
+		wg     gosync.WaitGroup
+		mu     gosync.Mutex
+		failed int
+	)
+	log.Noticef("Backfill start,from:%d,to:%d,workers:%d,chunk:%d", from, to, workers, chunk)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range chunks {
+				if err := backfillChunk(r.name, r.start, r.end, reset); err != nil {
+					log.Fatalf("Backfill chunk:%s [%d,%d] error:%s", r.name, r.start, r.end, err.Error())
+					mu.Lock()
+					failed++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("backfill from:%d to:%d, %d chunks failed, run again to resume", from, to, failed)
+	}
+
+	//4.the days of the range, and the new addresses of every later day
+	start := int64(-1)
+	if from > 1 {
+		block, err := (&model.Block{}).FindBlockByHeight(c.Mysql(), from)
+		if err != nil {
+			return fmt.Errorf("find block:%d error:%s, run daily-stats", from, err.Error())
+		}
+		start = block.F_timestamp
+	}
+	if err := RebuildDailyStats(start, time.Now().Unix()); err != nil {
+		return fmt.Errorf("%s, run daily-stats", err.Error())
+	}
+
+	log.Noticef("Backfill finish,from:%d,to:%d", from, to)
+	return nil
+}
+
+func backfillChunk(name string, start, end int64, reset bool) error {
+	if reset {
+		if err := model.DeleteSyncState(c.Mysql(), name); err != nil {
+			return err
+		}
+	}
+
+	//resume after the checkpoint of the chunk
+	state, err := (&model.SyncState{}).FindSyncState(c.Mysql(), name)
+	if err == nil && state.F_height >= start {
+		start = state.F_height + 1
+	}
+	if start > end {
+		log.Debugf("Backfill chunk:%s done before", name)
+		return nil
+	}
+
+	for height := start; height <= end; height++ {
+		for try := 1; ; try++ {
+			err = backfillBlock(name, height)
+			if err == nil {
+				break
+			}
+
+			log.Debugf("backfillBlock:%d try:%d error:%s", height, try, err.Error())
+			if e := model.AddSyncError(c.Mysql(), name, err); e != nil {
+				log.Debugf("AddSyncError error:%s", e.Error())
+			}
+			if try >= BACKFILL_RETRY {
+				return err
+			}
+			time.Sleep(time.Second * time.Duration(try))
+		}
+	}
+
+	log.Noticef("Backfill chunk:%s [%d,%d] success", name, start, end)
+	return nil
+}
+
+// backfillBlock write one height without the parent check of CommitBlock, the parent may be
+// in a chunk of another worker. A different block at the height is forked first
+func backfillBlock(name string, height int64) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("backfillBlock:%d panic:%v", height, r)
+		}
+	}()
+
+	data, err := FetchBlock(height)
+	if err != nil {
+		return err
+	}
+	chain_block := data.block
+
+	err = model.InTransaction(c.Mysql(), func(tx *gorm.DB) error {
+		databases_block, err := (&model.Block{}).FindBlockByHeight(tx, height)
+		if err != nil && err.Error() != DATA_NOT_EXIST {
+			return err
+		}
+		if err == nil && databases_block.F_hash != chain_block.Hash {
+			if err := dropBlock(tx, height); err != nil {
+				return err
+			}
+		}
+
+		if err := writeBlockData(tx, data); err != nil {
+			return err
+		}
+
+		return model.UpdateSyncHeight(tx, name, height, chain_block.Hash)
+	})
+	if err != nil {
+		return err
+	}
+	rememberTokens(data)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package sync
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+)
+
+// summarizeStats the block written is added to its day and the hour of its miner, and a forked one makes them
+// summarized again, in the transaction writing or forking it. Backfill turns it off: its workers write the days
+// concurrently and out of order, the new addresses of a day would miss the earlier days not written yet. It
+// rebuilds the days once the workers are done
+var summarizeStats = true
+
+// addStats add the block of data to its day and the hour of its miner, unless summarizeStats is off. The
+// addresses of the day are counted again only within STATSHEADLAG of the head, and once for the day
+// before when the first block of a day is written, catching up far behind the head stays cheap
+func addStats(db *gorm.DB, data *blockData) error {
+	if !summarizeStats {
+		return nil
+	}
+
+	block, err := (&model.Block{}).FindBlockByHeight(db, data.height)
+	if err != nil {
+		log.Debugf("FindBlockByHeight:%d error:%s", data.height, err.Error())
+		return err
+	}
+	if err = model.AddDailyStats(db, block); err != nil {
+		return err
+	}
+	if err = model.AddMinerHourly(db, block); err != nil {
+		return err
+	}
+
+	if data.height > 0 {
+		parent, err := (&model.Block{}).FindBlockByHeight(db, data.height-1)
+		if err != nil && err.Error() != DATA_NOT_EXIST {
+			log.Debugf("FindBlockByHeight:%d error:%s", data.height-1, err.Error())
+			return err
+		}
+		if err == nil && model.DayOf(parent.F_timestamp) != model.DayOf(block.F_timestamp) {
+			if err = model.RefreshDailyAddresses(db, parent.F_timestamp); err != nil {
+				log.Debugf("RefreshDailyAddresses:%d error:%s", parent.F_timestamp, err.Error())
+				return err
+			}
+		}
+	}
+	if data.head-data.height <= STATSHEADLAG {
+		if err = model.RefreshDailyAddresses(db, block.F_timestamp); err != nil {
+			log.Debugf("RefreshDailyAddresses:%d error:%s", block.F_timestamp, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// refreshStats summarize the UTC day and the hour of timestamp again, unless summarizeStats is off
+func refreshStats(db *gorm.DB, timestamp int64) error {
+	if !summarizeStats {
+		return nil
+	}
+
+	err := model.RefreshDailyStats(db, timestamp)
+	if err != nil {
+		log.Debugf("RefreshDailyStats:%d error:%s", timestamp, err.Error())
+		return err
+	}
+	err = model.RefreshMinerHourly(db, timestamp)
+	if err != nil {
+		log.Debugf("RefreshMinerHourly:%d error:%s", timestamp, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// RebuildDailyStats summarize the UTC days of [from, to] and the miners of their hours again, in day order
+// so the new addresses of a day see every earlier day, from -1 for the day of block 1. Each day is one
+// transaction under model.LockDay, the live syncer may write the last day meanwhile. Needed once after
+// upgrading, and after a backfill which stopped before rebuilding
+func RebuildDailyStats(from, to int64) error {
+	if from < 0 {
+		block, err := (&model.Block{}).FindBlockByHeight(c.Mysql(), 1)
+		if err != nil {
+			return fmt.Errorf("find block 1 error:%s", err.Error())
+		}
+		from = block.F_timestamp
+	}
+	from, to = model.DayOf(from), model.DayOf(to)
+
+	log.Noticef("RebuildDailyStats start,from:%d,to:%d", from, to)
+	for day := from; day <= to; day += model.DAYSECONDS {
+		err := model.InTransaction(c.Mysql(), func(tx *gorm.DB) error {
+			if err := model.RefreshDailyStats(tx, day); err != nil {
+				return fmt.Errorf("rebuild daily stats of day:%d error:%s", day, err.Error())
+			}
+			for hour := day; hour < day+model.DAYSECONDS; hour += model.HOURSECONDS {
+				if err := model.RefreshMinerHourly(tx, hour); err != nil {
+					return fmt.Errorf("rebuild miner stats of hour:%d error:%s", hour, err.Error())
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Noticef("RebuildDailyStats finish,from:%d,to:%d", from, to)
+	return nil
+}
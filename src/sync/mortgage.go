@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"github.com/EthereumHD/EhdChain/common"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"go-web3/dto"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+)
+
+// decodeMortgage decode a mortgage(address,uint256) or redeem(address,uint256) call to the mortgage contract
+func decodeMortgage(transaction dto.TransactionResponse) (txtype int64, addr string, amount *big.Int, ok bool) {
+	if strings.ToLower(transaction.To) != MORTGAGECONTRACTADDR {
+		return 0, "", nil, false
+	}
+
+	input := strings.ToLower(transaction.Input)
+	if strings.HasPrefix(input, MORTGAGECONTRACT_FUNC_MORTGAGE) {
+		txtype = model.TX_TYPE_ME_MORTGAGE
+	} else if strings.HasPrefix(input, MORTGAGECONTRACT_FUNC_REDEEM) {
+		txtype = model.TX_TYPE_ME_REDEEM
+	} else {
+		return 0, "", nil, false
+	}
+
+	//4 bytes selector, 32 bytes address, 32 bytes amount
+	data := common.FromHex(input)
+	if len(data) != 4+32+32 {
+		log.Debugf("mortgage call:%s with invalid input:%s", transaction.Hash, transaction.Input)
+		return 0, "", nil, false
+	}
+
+	addr = strings.ToLower(common.BytesToAddress(data[4:36]).Hex())
+	amount = big.NewInt(0).SetBytes(data[36:68])
+	return txtype, addr, amount, true
+}
+
+func WriteMortgages(db *gorm.DB, chain_block dto.Block, transactions map[string]dto.TransactionResponse, receipts map[string]dto.TransactionReceipt) error {
+
+	databases_mortgages := make([]model.Mortgage, 0)
+	for tx_hash, transaction := range transactions {
+		txtype, addr, amount, ok := decodeMortgage(transaction)
+		if !ok {
+			continue
+		}
+
+		databases_mortgage := model.Mortgage{}
+		databases_mortgage.F_tx_hash = tx_hash
+		databases_mortgage.F_block = chain_block.Number.Int64()
+		databases_mortgage.F_block_hash = chain_block.Hash
+		databases_mortgage.F_timestamp = chain_block.Timestamp.Int64()
+		databases_mortgage.F_tx_index = transaction.TransactionIndex.Int64()
+		databases_mortgage.F_from = strings.ToLower(transaction.From)
+		databases_mortgage.F_addr = addr
+		databases_mortgage.F_type = txtype
+		databases_mortgage.F_amount = amount.String()
+		databases_mortgage.F_value = transaction.Value.String()
+		databases_mortgage.F_success = 1
+		if status := receipts[tx_hash].Status; status != nil && status.Int64() == 0 {
+			databases_mortgage.F_success = 0
+		}
+
+		databases_mortgages = append(databases_mortgages, databases_mortgage)
+	}
+
+	err := model.CreateMortgages(db, databases_mortgages)
+	if err != nil {
+		log.Debugf("CreateMortgages,block:%d error:%s", chain_block.Number.Int64(), err.Error())
+		return err
+	}
+
+	log.Debugf("CreateMortgages success,block:%d,num:%d", chain_block.Number.Int64(), len(databases_mortgages))
+
+	return nil
+}
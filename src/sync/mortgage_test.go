@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"go-web3/dto"
+)
+
+func TestDecodeMortgage(t *testing.T) {
+	word := func(hex string) string {
+		return strings.Repeat("0", 64-len(hex)) + hex
+	}
+	addr := "00000000000000000000000000000000000000ab"
+	input := func(selector string, words ...string) string {
+		return selector + strings.Join(words, "")
+	}
+
+	tests := []struct {
+		name   string
+		to     string
+		input  string
+		txtype int64
+		addr   string
+		amount string
+		ok     bool
+	}{
+		{"mortgage", MORTGAGECONTRACTADDR, input(MORTGAGECONTRACT_FUNC_MORTGAGE, word(addr), word("de0b6b3a7640000")),
+			model.TX_TYPE_ME_MORTGAGE, "0x" + addr, "1000000000000000000", true},
+		{"redeem", MORTGAGECONTRACTADDR, input(MORTGAGECONTRACT_FUNC_REDEEM, word(addr), word("1")),
+			model.TX_TYPE_ME_REDEEM, "0x" + addr, "1", true},
+		{"upper case contract and 0X input", strings.ToUpper(MORTGAGECONTRACTADDR), strings.ToUpper(input(MORTGAGECONTRACT_FUNC_MORTGAGE, word(addr), word("ff"))),
+			model.TX_TYPE_ME_MORTGAGE, "0x" + addr, "255", true},
+		{"address word with dirty high bytes", MORTGAGECONTRACTADDR, input(MORTGAGECONTRACT_FUNC_MORTGAGE, "ffffffffffffffffffffffff"+addr, word("0")),
+			model.TX_TYPE_ME_MORTGAGE, "0x" + addr, "0", true},
+		{"other contract", ZEROADDR, input(MORTGAGECONTRACT_FUNC_MORTGAGE, word(addr), word("1")), 0, "", "", false},
+		{"unknown selector", MORTGAGECONTRACTADDR, input("0xa9059cbb", word(addr), word("1")), 0, "", "", false},
+		{"plain transfer", MORTGAGECONTRACTADDR, "0x", 0, "", "", false},
+		{"selector only", MORTGAGECONTRACTADDR, MORTGAGECONTRACT_FUNC_MORTGAGE, 0, "", "", false},
+		{"missing amount", MORTGAGECONTRACTADDR, input(MORTGAGECONTRACT_FUNC_MORTGAGE, word(addr)), 0, "", "", false},
+		{"extra word", MORTGAGECONTRACTADDR, input(MORTGAGECONTRACT_FUNC_REDEEM, word(addr), word("1"), word("2")), 0, "", "", false},
+		{"odd length", MORTGAGECONTRACTADDR, input(MORTGAGECONTRACT_FUNC_REDEEM, word(addr), word("1")) + "0", 0, "", "", false},
+	}
+
+	for _, test := range tests {
+		transaction := dto.TransactionResponse{Hash: "0x01", To: test.to, Input: test.input}
+		txtype, addr, amount, ok := decodeMortgage(transaction)
+		if ok != test.ok {
+			t.Errorf("%s: ok=%t, want %t", test.name, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if txtype != test.txtype || addr != test.addr || amount.String() != test.amount {
+			t.Errorf("%s: got type=%d addr=%s amount=%s, want type=%d addr=%s amount=%s", test.name,
+				txtype, addr, amount, test.txtype, test.addr, test.amount)
+		}
+	}
+}
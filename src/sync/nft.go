@@ -0,0 +1,216 @@
+package sync
+
+import (
+	"fmt"
+	"github.com/EthereumHD/EhdChain/common"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"go-web3/dto"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+	"sort"
+	"strings"
+)
+
+// nftTransfer is an ERC-721 Transfer log of a block
+type nftTransfer struct {
+	token    string
+	tokenId  string
+	from     string
+	to       string
+	txHash   string
+	logIndex int64
+}
+
+func (t nftTransfer) key() string {
+	return t.token + ":" + t.tokenId
+}
+
+// decodeNftTransfer decode an ERC-721 Transfer(address indexed,address indexed,uint256 indexed) log
+func decodeNftTransfer(chain_log dto.TransactionLogs) (transfer nftTransfer, ok bool) {
+	if len(chain_log.Topics) != 4 || strings.ToLower(chain_log.Topics[0]) != TOKEN_TRANSFER_TOPIC {
+		return transfer, false
+	}
+	if len(common.FromHex(chain_log.Data)) != 0 {
+		return transfer, false
+	}
+
+	transfer.token = strings.ToLower(chain_log.Address)
+	transfer.tokenId = big.NewInt(0).SetBytes(common.FromHex(chain_log.Topics[3])).String()
+	transfer.from = topicToAddress(chain_log.Topics[1])
+	transfer.to = topicToAddress(chain_log.Topics[2])
+	transfer.txHash = chain_log.TransactionHash
+	transfer.logIndex = chain_log.LogIndex.Int64()
+	return transfer, true
+}
+
+// decodeNftTransfers all ERC-721 transfers of the receipts, in log order
+func decodeNftTransfers(receipts map[string]dto.TransactionReceipt) []nftTransfer {
+	transfers := make([]nftTransfer, 0)
+	for _, receipt := range receipts {
+		for _, chain_log := range receipt.Logs {
+			if transfer, ok := decodeNftTransfer(chain_log); ok {
+				transfers = append(transfers, transfer)
+			}
+		}
+	}
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].logIndex < transfers[j].logIndex })
+	return transfers
+}
+
+// fetchNfts get name and symbol of the ERC-721 contracts not committed by this process yet,
+// and the tokenURI of the token ids minted in the block
+func fetchNfts(data *blockData) {
+	for _, transfer := range decodeNftTransfers(data.receipts) {
+		if _, ok := data.tokens[transfer.token]; !ok {
+			if _, known := knownTokens.Load(transfer.token); !known {
+				data.tokens[transfer.token] = model.Token{
+					F_address:      transfer.token,
+					F_type:         TOKEN_TYPE_ERC721,
+					F_name:         decodeStringResult(callToken(transfer.token, data.height, TOKEN_FUNC_NAME)),
+					F_symbol:       decodeStringResult(callToken(transfer.token, data.height, TOKEN_FUNC_SYMBOL)),
+					F_decimals:     0,
+					F_supply_block: -1,
+				}
+			}
+		}
+
+		if transfer.from == ZEROADDR {
+			data.tokenURIs[transfer.key()] = FetchTokenURI(transfer.token, transfer.tokenId, data.height)
+		}
+	}
+}
+
+// FetchTokenURI call tokenURI(tokenId) of an ERC-721 contract at height, so a backfilled mint records the URI
+// of its own time and one burned later still has it. Empty if it does not implement the metadata extension
+func FetchTokenURI(token string, tokenId string, height int64) string {
+	tokenIdInt, b := big.NewInt(0).SetString(tokenId, 10)
+	if b == false {
+		return ""
+	}
+
+	contract, err := c.Web3().Eth.NewContract(ERC721_ABI)
+	if err != nil {
+		log.Debugf("NewContract error:%s", err.Error())
+		return ""
+	}
+
+	result, err := contract.CallAt(&dto.TransactionParameters{To: token}, fmt.Sprintf("0x%x", height), "tokenURI", tokenIdInt)
+	if err != nil {
+		log.Debugf("Contract.Call,token:%s,tokenURI(%s) error:%s", token, tokenId, err.Error())
+		return ""
+	}
+
+	ret, err := result.ToString()
+	if err != nil {
+		log.Debugf("Contract.Call,token:%s,tokenURI(%s) error:%s", token, tokenId, err.Error())
+		return ""
+	}
+
+	return decodeStringResult(common.FromHex(ret))
+}
+
+func WriteNftTransfers(db *gorm.DB, chain_block dto.Block, receipts map[string]dto.TransactionReceipt,
+	tokens map[string]model.Token, tokenURIs map[string]string) error {
+	height := chain_block.Number.Int64()
+
+	databases_transfers := make([]model.NftTransfer, 0)
+	databases_owners := make([]model.NftOwner, 0)
+	databases_tokens := make([]model.Token, 0)
+	seen := make(map[string]bool)
+	for _, transfer := range decodeNftTransfers(receipts) {
+		databases_transfer := model.NftTransfer{}
+		databases_transfer.F_block = height
+		databases_transfer.F_block_hash = chain_block.Hash
+		databases_transfer.F_timestamp = chain_block.Timestamp.Int64()
+		databases_transfer.F_tx_hash = transfer.txHash
+		databases_transfer.F_log_index = transfer.logIndex
+		databases_transfer.F_token = transfer.token
+		databases_transfer.F_token_id = transfer.tokenId
+		databases_transfer.F_from = transfer.from
+		databases_transfer.F_to = transfer.to
+		databases_transfers = append(databases_transfers, databases_transfer)
+
+		databases_owners = append(databases_owners, model.NftOwner{
+			F_token:     transfer.token,
+			F_token_id:  transfer.tokenId,
+			F_owner:     transfer.to,
+			F_block:     height,
+			F_log_index: transfer.logIndex,
+			F_token_uri: tokenURIs[transfer.key()],
+		})
+
+		if !seen[transfer.token] {
+			seen[transfer.token] = true
+			token, ok := tokens[transfer.token]
+			if !ok {
+				token = model.Token{F_address: transfer.token, F_type: TOKEN_TYPE_ERC721, F_decimals: -1, F_supply_block: -1}
+			}
+			token.F_block = height
+			databases_tokens = append(databases_tokens, token)
+		}
+	}
+	if len(databases_transfers) == 0 {
+		return nil
+	}
+
+	sort.Slice(databases_tokens, func(i, j int) bool { return databases_tokens[i].F_address < databases_tokens[j].F_address })
+	err := model.CreateTokens(db, databases_tokens)
+	if err != nil {
+		log.Debugf("CreateTokens,block:%d error:%s", height, err.Error())
+		return err
+	}
+
+	err = model.CreateNftTransfers(db, databases_transfers)
+	if err != nil {
+		log.Debugf("CreateNftTransfers,block:%d error:%s", height, err.Error())
+		return err
+	}
+
+	err = model.UpdateNftOwners(db, databases_owners)
+	if err != nil {
+		log.Debugf("UpdateNftOwners,block:%d error:%s", height, err.Error())
+		return err
+	}
+
+	log.Debugf("CreateNftTransfers success,block:%d,num:%d", height, len(databases_transfers))
+
+	return nil
+}
+
+// dropNftTransfers set the NORMAL ERC-721 transfers of height FORK and give their token ids back to
+// the owner of the latest remaining transfer
+func dropNftTransfers(db *gorm.DB, height int64) error {
+	transfers, err := model.FindNftTransfersByHeight(db, height)
+	if err != nil {
+		log.Debugf("FindNftTransfersByHeight,error:%s", err.Error())
+		return err
+	}
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	err = model.UpdateNftTransferStatusByHeight(db, height, FORK)
+	if err != nil {
+		log.Debugf("UpdateNftTransferStatusByHeight,error:%s", err.Error())
+		return err
+	}
+
+	reset := make(map[[2]string]bool)
+	for _, transfer := range transfers {
+		key := [2]string{transfer.F_token, transfer.F_token_id}
+		if reset[key] {
+			continue
+		}
+		reset[key] = true
+
+		err = model.ResetNftOwner(db, transfer.F_token, transfer.F_token_id)
+		if err != nil {
+			log.Debugf("ResetNftOwner,token:%s,id:%s error:%s", transfer.F_token, transfer.F_token_id, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package sync
+
+import (
+	"fmt"
+	"qoobing.com/utillib.golang/log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Pipeline fetch blocks with several workers ahead of the committed height,
+// and commit them one by one in height order
+type Pipeline struct {
+	workers  int
+	prefetch int64
+
+	fetchBlock  func(height int64) (*blockData, error) //FetchBlock
+	commitBlock func(data *blockData) error            //CommitBlock
+
+	blocks       int64 //committed blocks, atomic
+	transactions int64 //committed transactions, atomic
+	height       int64 //last committed height, atomic
+
+	mu    sync.RWMutex
+	stats SyncStats
+}
+
+// SyncStats is the throughput of the pipeline over the last report interval
+type SyncStats struct {
+	Height       int64   `json:"height"`
+	Blocks       int64   `json:"blocks"`
+	Transactions int64   `json:"transactions"`
+	BlockRate    float64 `json:"block_rate"` //blocks per second
+	TxRate       float64 `json:"tx_rate"`    //transactions per second
+	Workers      int     `json:"workers"`
+	Prefetch     int64   `json:"prefetch"`
+}
+
+type fetchResult struct {
+	data *blockData
+	err  error
+}
+
+var pipeline *Pipeline
+
+func NewPipeline(workers int, prefetch int64) *Pipeline {
+	if workers <= 0 {
+		workers = 1
+	}
+	if prefetch < int64(workers) {
+		prefetch = int64(workers)
+	}
+
+	//make sure the lazy connections are created before workers share them
+	c.Web3()
+	c.Mysql()
+
+	return &Pipeline{workers: workers, prefetch: prefetch, fetchBlock: FetchBlock, commitBlock: CommitBlock, height: -1}
+}
+
+// Run sync heights [from, to], it returns after the first failed height,
+// the caller should start again from the height after c.GetBlockNOw()
+func (p *Pipeline) Run(from, to int64) error {
+	if to < from {
+		return nil
+	}
+
+	//one round never holds more than 4 windows of results
+	head := to
+	if to-from+1 > p.prefetch*4 {
+		to = from + p.prefetch*4 - 1
+	}
+
+	results := make([]chan fetchResult, to-from+1)
+	for i := range results {
+		results[i] = make(chan fetchResult, 1)
+	}
+
+	var (
+		jobs   = make(chan int64)
+		window = make(chan struct{}, p.prefetch)
+		quit   = make(chan struct{})
+		wg     sync.WaitGroup
+	)
+	defer wg.Wait()
+	defer close(quit)
+
+	//feeder, never run more than prefetch blocks ahead of the committer
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for h := from; h <= to; h++ {
+			select {
+			case window <- struct{}{}:
+			case <-quit:
+				return
+			}
+			select {
+			case jobs <- h:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	//workers
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for h := range jobs {
+				results[h-from] <- p.fetch(h)
+			}
+		}()
+	}
+
+	//committer
+	for h := from; h <= to; h++ {
+		r := <-results[h-from]
+		<-window
+		if r.err != nil {
+			log.Debugf("FetchBlock:%d error:%s", h, r.err.Error())
+			return r.err
+		}
+
+		r.data.head = head
+		if err := p.commitBlock(r.data); err != nil {
+			log.Debugf("CommitBlock:%d error:%s", h, err.Error())
+			return err
+		}
+
+		log.Debugf("SyncOneBlock:%d success", h)
+		c.AddBlockNow(1)
+
+		atomic.AddInt64(&p.blocks, 1)
+		atomic.AddInt64(&p.transactions, int64(len(r.data.transactions)))
+		atomic.StoreInt64(&p.height, h)
+	}
+
+	return nil
+}
+
+func (p *Pipeline) fetch(height int64) (r fetchResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			r = fetchResult{err: fmt.Errorf("FetchBlock:%d panic:%v", height, err)}
+		}
+	}()
+
+	data, err := p.fetchBlock(height)
+	return fetchResult{data: data, err: err}
+}
+
+// Report log the throughput every interval, and keep it for Stats
+func (p *Pipeline) Report(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	var (
+		last   = time.Now()
+		blocks = atomic.LoadInt64(&p.blocks)
+		txs    = atomic.LoadInt64(&p.transactions)
+	)
+	for {
+		time.Sleep(interval)
+
+		now := time.Now()
+		stats := SyncStats{
+			Height:       atomic.LoadInt64(&p.height),
+			Blocks:       atomic.LoadInt64(&p.blocks),
+			Transactions: atomic.LoadInt64(&p.transactions),
+			Workers:      p.workers,
+			Prefetch:     p.prefetch,
+		}
+		seconds := now.Sub(last).Seconds()
+		stats.BlockRate = float64(stats.Blocks-blocks) / seconds
+		stats.TxRate = float64(stats.Transactions-txs) / seconds
+
+		p.mu.Lock()
+		p.stats = stats
+		p.mu.Unlock()
+
+		log.Noticef("sync throughput,height:%d,blocks:%d(%.2f/s),transactions:%d(%.2f/s),workers:%d,prefetch:%d",
+			stats.Height, stats.Blocks, stats.BlockRate, stats.Transactions, stats.TxRate, p.workers, p.prefetch)
+
+		last, blocks, txs = now, stats.Blocks, stats.Transactions
+	}
+}
+
+// Stats return the last reported throughput, zero if the syncer is not running
+func Stats() SyncStats {
+	if pipeline == nil {
+		return SyncStats{Height: -1}
+	}
+
+	pipeline.mu.RLock()
+	defer pipeline.mu.RUnlock()
+	return pipeline.stats
+}
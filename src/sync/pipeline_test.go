@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPipelineRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       int64
+		to         int64
+		workers    int
+		prefetch   int64
+		fetchFail  int64 //height FetchBlock fails at, 0 for none
+		commitFail int64 //height CommitBlock fails at, 0 for none
+		committed  int64 //last committed height, from-1 for none
+		err        bool
+	}{
+		{"in order", 1, 10, 4, 4, 0, 0, 10, false},
+		{"one worker", 1, 10, 1, 1, 0, 0, 4, false}, //one window of 1 is capped to 4 heights
+		{"capped to 4 windows", 1, 30, 2, 3, 0, 0, 12, false},
+		{"fetch error", 1, 10, 4, 4, 6, 0, 5, true},
+		{"commit error", 1, 10, 4, 4, 0, 4, 3, true},
+		{"to before from", 5, 4, 4, 4, 0, 0, 4, false},
+	}
+
+	for _, test := range tests {
+		var (
+			last      = test.from - 1 //atomic
+			ahead     int64           //heights fetched more than a window ahead of the committer, atomic
+			committed = make([]int64, 0)
+		)
+		p := &Pipeline{workers: test.workers, prefetch: test.prefetch, height: -1}
+		p.fetchBlock = func(height int64) (*blockData, error) {
+			//the higher heights of a window are fetched first
+			time.Sleep(time.Duration((test.to-height)%4) * time.Millisecond)
+			if height-atomic.LoadInt64(&last) > test.prefetch+1 {
+				atomic.AddInt64(&ahead, 1)
+			}
+			if height == test.fetchFail {
+				return nil, errors.New("fetch failed")
+			}
+			return &blockData{height: height}, nil
+		}
+		p.commitBlock = func(data *blockData) error {
+			if data.height == test.commitFail {
+				return errors.New("commit failed")
+			}
+			if data.head != test.to {
+				t.Errorf("%s: block:%d committed with head %d, want %d", test.name, data.height, data.head, test.to)
+			}
+			committed = append(committed, data.height)
+			atomic.StoreInt64(&last, data.height)
+			return nil
+		}
+
+		c.SetBlockNow(test.from - 1)
+		err := p.Run(test.from, test.to)
+		if (err != nil) != test.err {
+			t.Errorf("%s: err=%v, want error %v", test.name, err, test.err)
+		}
+
+		if int64(len(committed)) != test.committed-test.from+1 {
+			t.Errorf("%s: committed %v, want %d to %d", test.name, committed, test.from, test.committed)
+			continue
+		}
+		for i, height := range committed {
+			if height != test.from+int64(i) {
+				t.Errorf("%s: committed %v, not in height order", test.name, committed)
+				break
+			}
+		}
+		if c.GetBlockNOw() != test.committed || (len(committed) > 0 && p.height != test.committed) {
+			t.Errorf("%s: block now %d, pipeline height %d, want %d", test.name, c.GetBlockNOw(), p.height,
+				test.committed)
+		}
+		if ahead > 0 {
+			t.Errorf("%s: %d heights fetched more than a window ahead", test.name, ahead)
+		}
+	}
+}
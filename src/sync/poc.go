@@ -0,0 +1,49 @@
+package sync
+
+import (
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"go-web3/dto"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+)
+
+// fetchPoc get the proof of the block, the genesis block has none
+func fetchPoc(data *blockData) error {
+	poc, err := c.Web3().Eth.GetBlockPocByNumber(big.NewInt(data.height))
+	if err != nil {
+		if err.Error() == EMPTY_RSP {
+			log.Debugf("Eth.GetBlockPocByNumber:%d is NULL", data.height)
+			return nil
+		}
+		log.Debugf("Eth.GetBlockPocByNumber:%d error:%s", data.height, err.Error())
+		return err
+	}
+
+	data.poc = poc
+	return nil
+}
+
+func WriteBlockPoc(db *gorm.DB, chain_block dto.Block, poc *dto.Poc) error {
+	if poc == nil {
+		return nil
+	}
+
+	databases_poc := model.BlockPoc{}
+	databases_poc.F_block = chain_block.Number.Int64()
+	databases_poc.F_block_hash = chain_block.Hash
+	databases_poc.F_miner = chain_block.Miner
+	databases_poc.F_timestamp = chain_block.Timestamp.Int64()
+	databases_poc.F_deadline = poc.Deadline.String()
+	databases_poc.F_nonce = poc.Nonce.String()
+	databases_poc.F_scoop = poc.ScoopNumber.Int64()
+
+	err := model.CreateBlockPoc(db, databases_poc)
+	if err != nil {
+		log.Debugf("CreateBlockPoc,block:%d error:%s", databases_poc.F_block, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package sync
+
+import (
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+
+	"errors"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"go-web3/dto"
+	"qoobing.com/utillib.golang/log"
+	"strings"
+	"time"
+)
+
+// errReorganized is returned after the databases is rolled back to the common ancestor,
+// the syncer should continue from c.GetBlockNOw()
+var errReorganized = errors.New("chain reorganized")
+
+// rollbackReorg walk parent hashes of new_block back to the common ancestor with databases,
+// then fork all orphaned blocks, transcations and miner rewards and record the reorg in one transaction
+func rollbackReorg(new_block *dto.Block) error {
+	height := new_block.Number.Int64()
+
+	//1.walk back to the common ancestor
+	findBlock := func(height int64) (model.Block, error) {
+		return (&model.Block{}).FindBlockByHeight(c.Mysql(), height)
+	}
+	parentHash := func(hash string) (string, error) {
+		chain_block, err := c.Web3().Eth.GetBlockByHash(hash, false)
+		if err != nil {
+			return "", err
+		}
+		return chain_block.ParentHash, nil
+	}
+	ancestor, hash, orphans, err := findAncestor(height, new_block.ParentHash, findBlock, parentHash)
+	if err != nil {
+		return err
+	}
+
+	//the parent matches by now, or only empty heights were walked over: nothing to fork,
+	//sync again from the ancestor
+	if len(orphans) == 0 {
+		log.Noticef("No orphan for new block:%d,hash:%s,sync again from ancestor:%d,hash:%s",
+			height, new_block.Hash, ancestor, hash)
+		if err := model.UpdateSyncHeight(c.Mysql(), model.SYNC_STATE_LIVE, ancestor, hash); err != nil {
+			return err
+		}
+		c.SetBlockNow(ancestor)
+		return errReorganized
+	}
+
+	log.Noticef("Find reorg,new block:%d,hash:%s,ancestor:%d,hash:%s,depth:%d",
+		height, new_block.Hash, ancestor, hash, len(orphans))
+
+	//2.roll back from the old head to the ancestor
+	hashes := make([]string, 0, len(orphans))
+	for _, orphan := range orphans {
+		hashes = append(hashes, orphan.F_hash)
+	}
+
+	reorg := model.Reorg{
+		F_ancestor:        ancestor,
+		F_ancestor_hash:   hash,
+		F_depth:           int64(len(orphans)),
+		F_old_head:        orphans[0].F_block,
+		F_old_head_hash:   orphans[0].F_hash,
+		F_new_head:        height,
+		F_new_head_hash:   new_block.Hash,
+		F_orphaned_hashes: strings.Join(hashes, ","),
+		F_timestamp:       time.Now().Unix(),
+	}
+
+	err = model.InTransaction(c.Mysql(), func(tx *gorm.DB) error {
+		for _, orphan := range orphans {
+			if err := dropBlock(tx, orphan.F_block); err != nil {
+				log.Debugf("dropBlock:%d error:%s", orphan.F_block, err.Error())
+				return err
+			}
+		}
+
+		if err := reorg.CreateReorg(tx); err != nil {
+			log.Debugf("CreateReorg error:%s", err.Error())
+			return err
+		}
+
+		if err := model.UpdateSyncHeight(tx, model.SYNC_STATE_LIVE, ancestor, hash); err != nil {
+			return err
+		}
+		return model.AddSyncReorg(tx, model.SYNC_STATE_LIVE)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.SetBlockNow(ancestor)
+	return errReorganized
+}
+
+// findAncestor walk back from parent_hash, the hash of the new chain at height-1, until the databases block has
+// the hash of the new chain. A height backfill left empty has nothing to fork and the walk goes on to its parent.
+// orphans are the databases blocks walked over, the highest first
+func findAncestor(height int64, parent_hash string, findBlock func(height int64) (model.Block, error),
+	parentHash func(hash string) (string, error)) (ancestor int64, hash string, orphans []model.Block, err error) {
+
+	orphans = make([]model.Block, 0)
+	hash = parent_hash
+	for ancestor = height - 1; ancestor >= 0; ancestor-- {
+		databases_block, err := findBlock(ancestor)
+		if err != nil && err.Error() != DATA_NOT_EXIST {
+			log.Debugf("FindBlockByHeight:%d error:%s", ancestor, err.Error())
+			return 0, "", nil, err
+		}
+		if err == nil && databases_block.F_hash == hash {
+			return ancestor, hash, orphans, nil
+		}
+
+		if height-1-ancestor >= MAXREORGDEPTH {
+			return 0, "", nil, fmt.Errorf("reorg at height:%d deeper than %d blocks", height, MAXREORGDEPTH)
+		}
+		if err == nil {
+			orphans = append(orphans, databases_block)
+		}
+
+		parent, err := parentHash(hash)
+		if err != nil {
+			log.Debugf("Eth.GetBlockByHash:%s error:%s", hash, err.Error())
+			return 0, "", nil, err
+		}
+		hash = parent
+	}
+
+	return 0, "", nil, fmt.Errorf("reorg at height:%d has no common ancestor", height)
+}
@@ -0,0 +1,114 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+)
+
+// fakeChain the new chain has the hash "a<height>" at every height, the parent of its genesis is zero
+func fakeChain(hash string) (string, error) {
+	var height int64
+	if _, err := fmt.Sscanf(hash, "a%d", &height); err != nil {
+		return "", errors.New("block not found:" + hash)
+	}
+	if height == 0 {
+		return "0x0", nil
+	}
+	return fmt.Sprintf("a%d", height-1), nil
+}
+
+// fakeDatabases the hashes of the databases blocks by height, a missing height was left empty
+func fakeDatabases(blocks map[int64]string) func(height int64) (model.Block, error) {
+	return func(height int64) (model.Block, error) {
+		hash, ok := blocks[height]
+		if !ok {
+			return model.Block{}, errors.New(DATA_NOT_EXIST)
+		}
+		return model.Block{F_block: height, F_hash: hash}, nil
+	}
+}
+
+// forkedDatabases the old chain "b<height>" over (ancestor, to], on the new chain up to ancestor
+func forkedDatabases(ancestor, to int64) map[int64]string {
+	blocks := make(map[int64]string)
+	for h := int64(0); h <= to; h++ {
+		if h <= ancestor {
+			blocks[h] = fmt.Sprintf("a%d", h)
+		} else {
+			blocks[h] = fmt.Sprintf("b%d", h)
+		}
+	}
+	return blocks
+}
+
+// descending the heights from down to to
+func descending(from, to int64) []int64 {
+	heights := make([]int64, 0)
+	for h := from; h >= to; h-- {
+		heights = append(heights, h)
+	}
+	return heights
+}
+
+func TestFindAncestor(t *testing.T) {
+	deep := int64(MAXREORGDEPTH + 10)
+
+	tests := []struct {
+		name     string
+		height   int64
+		blocks   map[int64]string
+		ancestor int64
+		orphans  []int64
+		err      string
+	}{
+		{"parent matches", 10, forkedDatabases(9, 9), 9, nil, ""},
+		{"ancestor walk", 10, forkedDatabases(7, 9), 7, []int64{9, 8}, ""},
+		{"old chain longer", 10, forkedDatabases(7, 12), 7, []int64{9, 8}, ""},
+		{"empty heights skipped", 10, map[int64]string{6: "a6", 7: "b7", 9: "b9"}, 6, []int64{9, 7}, ""},
+		{"only empty heights", 10, map[int64]string{6: "a6"}, 6, nil, ""},
+		{"at the depth cap", deep, forkedDatabases(deep-1-MAXREORGDEPTH, deep-1), deep - 1 - MAXREORGDEPTH,
+			descending(deep-1, deep-MAXREORGDEPTH), ""},
+		{"deeper than the cap", deep, forkedDatabases(deep-2-MAXREORGDEPTH, deep-1), 0, nil, "deeper than"},
+		{"no common ancestor", 3, map[int64]string{0: "b0", 1: "b1", 2: "b2"}, 0, nil, "no common ancestor"},
+	}
+
+	for _, test := range tests {
+		ancestor, hash, orphans, err := findAncestor(test.height, fmt.Sprintf("a%d", test.height-1),
+			fakeDatabases(test.blocks), fakeChain)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: err=%v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected err=%q", test.name, err)
+			continue
+		}
+
+		if ancestor != test.ancestor || hash != fmt.Sprintf("a%d", test.ancestor) {
+			t.Errorf("%s: ancestor %d:%s, want %d", test.name, ancestor, hash, test.ancestor)
+		}
+		if len(orphans) != len(test.orphans) {
+			t.Errorf("%s: %d orphans, want heights %v", test.name, len(orphans), test.orphans)
+			continue
+		}
+		for i, orphan := range orphans {
+			if orphan.F_block != test.orphans[i] || orphan.F_hash != fmt.Sprintf("b%d", test.orphans[i]) {
+				t.Errorf("%s: orphan %d is %d:%s, want %d", test.name, i, orphan.F_block, orphan.F_hash, test.orphans[i])
+			}
+		}
+	}
+}
+
+func TestFindAncestorChainError(t *testing.T) {
+	_, _, _, err := findAncestor(10, "c9", fakeDatabases(forkedDatabases(7, 9)), fakeChain)
+	if err == nil || !strings.Contains(err.Error(), "block not found:c9") {
+		t.Errorf("walk over a block missing on the chain: err=%v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package sync
+
+import (
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+)
+
+// settleBlockReward make the t_reward_ledger entries of a block agree with its status: a NORMAL block is
+// credited once with its reward and fees, any other is not credited. The amounts of an appended entry are added
+// to the miner totals in the same transaction. Settling a block twice appends nothing
+func settleBlockReward(db *gorm.DB, block model.Block) (changed bool, err error) {
+	last, err := model.FindLastRewardEntry(db, block.F_hash)
+	if err != nil && err.Error() != DATA_NOT_EXIST {
+		return false, err
+	}
+	found := err == nil
+
+	seq := int64(0)
+	credited := false
+	if found {
+		seq = last.F_seq + 1
+		credited = last.F_sign > 0
+	}
+
+	//reverse the credit of a forked block, or of one whose amounts were changed
+	if credited && (block.F_status != NORMAL || !sameAmount(last.F_reward, block.F_reward) || !sameAmount(last.F_fees, block.F_fees)) {
+		entry := last
+		entry.F_seq = seq
+		entry.F_sign = -1
+		entry.F_reward = negAmount(last.F_reward)
+		entry.F_fees = negAmount(last.F_fees)
+		if err = model.CreateRewardEntry(db, entry); err != nil {
+			return false, err
+		}
+		if err = model.AddMinerReward(db, entry.F_miner, entry.F_reward, entry.F_fees); err != nil {
+			return false, err
+		}
+		log.Debugf("settleBlockReward,block:%d,hash:%s reversed", block.F_block, block.F_hash)
+
+		seq++
+		credited = false
+		changed = true
+	}
+
+	if !credited && block.F_status == NORMAL {
+		entry := model.RewardEntry{
+			F_block:      block.F_block,
+			F_block_hash: block.F_hash,
+			F_seq:        seq,
+			F_miner:      block.F_miner,
+			F_timestamp:  block.F_timestamp,
+			F_sign:       1,
+			F_reward:     toAmount(block.F_reward).String(),
+			F_fees:       toAmount(block.F_fees).String(),
+		}
+		if err = model.CreateRewardEntry(db, entry); err != nil {
+			return false, err
+		}
+		if err = model.AddMinerReward(db, entry.F_miner, entry.F_reward, entry.F_fees); err != nil {
+			return false, err
+		}
+		log.Debugf("settleBlockReward,block:%d,hash:%s credited", block.F_block, block.F_hash)
+
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// toAmount parse a decimal amount column, a block synced without fees counts as 0
+func toAmount(value string) *big.Int {
+	amount, b := big.NewInt(0).SetString(value, 10)
+	if b == false {
+		return big.NewInt(0)
+	}
+	return amount
+}
+
+func negAmount(value string) string {
+	amount := toAmount(value)
+	return amount.Neg(amount).String()
+}
+
+func sameAmount(a string, b string) bool {
+	return toAmount(a).Cmp(toAmount(b)) == 0
+}
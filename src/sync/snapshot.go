@@ -0,0 +1,41 @@
+package sync
+
+import (
+	"fmt"
+	"github.com/EthereumHD/Scan/src/config"
+	"github.com/EthereumHD/Scan/src/metrics"
+	"github.com/EthereumHD/Scan/src/model"
+	"qoobing.com/utillib.golang/log"
+)
+
+// snapshotSummary write the network summary at a committed block into t_summary_history every
+// SnapshotInterval blocks. A failed snapshot is only logged, the next one is taken on schedule
+func snapshotSummary(height int64) {
+	if height%config.Config().Metrics.SnapshotInterval != 0 {
+		return
+	}
+
+	block, err := (&model.Block{}).FindBlockByHeight(c.Mysql(), height)
+	if err != nil {
+		log.Debugf("snapshotSummary FindBlockByHeight:%d error:%s", height, err.Error())
+		return
+	}
+
+	summary, err := metrics.Compute(c.Mysql(), block)
+	if err != nil {
+		log.Debugf("snapshotSummary metrics.Compute:%d error:%s", height, err.Error())
+		return
+	}
+
+	hexHeight := fmt.Sprintf("0x%x", height)
+	if summary.TotalRewarded, err = c.Web3().Eth.GetTotalRewarded(hexHeight); err != nil {
+		log.Debugf("snapshotSummary GetTotalRewarded:%d error:%s", height, err.Error())
+	}
+	if summary.TotalMortgage, err = c.Web3().Eth.GetTotalMortgage(hexHeight); err != nil {
+		log.Debugf("snapshotSummary GetTotalMortgage:%d error:%s", height, err.Error())
+	}
+
+	if err := model.CreateSummaryHistory(c.Mysql(), summary.ToHistory()); err != nil {
+		log.Debugf("snapshotSummary CreateSummaryHistory:%d error:%s", height, err.Error())
+	}
+}
@@ -0,0 +1,95 @@
+package sync
+
+import (
+	"fmt"
+	"github.com/EthereumHD/Scan/src/config"
+	"github.com/EthereumHD/Scan/src/model"
+
+	"go-web3"
+	"go-web3/dto"
+	"go-web3/providers"
+	"qoobing.com/utillib.golang/log"
+	"time"
+)
+
+// FOLLOWRETRIES the failed pipeline runs in a row before following newHeads gives up to polling
+const FOLLOWRETRIES = 3
+
+// followNewHeads subscribe newHeads on address and sync up to every head as soon as it arrives,
+// it blocks until the subscription drops, no head arrives within HeadTimeout or the pipeline keeps
+// failing, the caller falls back to polling then. The error is already counted in t_sync_state
+func followNewHeads(address string) error {
+	provider := providers.NewWebSocketProvider(address)
+	defer provider.Close()
+
+	subscription, err := web3.NewWeb3(provider).Eth.SubscribeNewHeads()
+	if err != nil {
+		log.Debugf("SubscribeNewHeads:%s error:%s", address, err.Error())
+		recordSyncError(err)
+		return err
+	}
+	log.Noticef("Subscribe newHeads:%s success,subscription:%s", address, subscription.ID)
+
+	timeout := time.Second * time.Duration(config.Config().Sync.HeadTimeout)
+	dropped := uint64(0)
+	for {
+		var notification []byte
+		var ok bool
+		select {
+		case notification, ok = <-subscription.Notifications():
+			if !ok {
+				err = subscription.Err()
+				if err != nil {
+					recordSyncError(err)
+				}
+				return err
+			}
+		case <-time.After(timeout):
+			err = fmt.Errorf("no newHeads from %s in %s", address, timeout)
+			recordSyncError(err)
+			return err
+		}
+
+		//a full buffer drops the newest heads, the next head syncs past them anyway
+		if n := subscription.Dropped(); n > dropped {
+			log.Noticef("subscription:%s dropped %d newHeads notifications", subscription.ID, n-dropped)
+			dropped = n
+		}
+
+		head := dto.Header{}
+		if err := head.UnmarshalJSON(notification); err != nil {
+			log.Debugf("newHeads notification:%s error:%s", string(notification), err.Error())
+			continue
+		}
+		log.Debugf("\n\nnewHeads hegiht:%d,hash:%s", head.Number.Int64(), head.Hash)
+
+		if err := model.UpdateSyncHead(c.Mysql(), model.SYNC_STATE_LIVE, head.Number.Int64()); err != nil {
+			log.Debugf("UpdateSyncHead error:%s", err.Error())
+		}
+
+		if err := syncToHead(head.Number.Int64()); err != nil {
+			return err
+		}
+	}
+}
+
+// syncToHead run the pipeline up to head, a reorg is not a failure. Each failed run is counted in t_sync_state
+func syncToHead(head int64) error {
+	failures := 0
+	for head > c.GetBlockNOw() {
+		err := pipeline.Run(c.GetBlockNOw()+1, head)
+		if err == nil || err == errReorganized {
+			failures = 0
+			continue
+		}
+
+		log.Debugf("Pipeline.Run error:%s", err.Error())
+		recordSyncError(err)
+		if failures++; failures >= FOLLOWRETRIES {
+			return fmt.Errorf("sync to head:%d failed %d times,last error:%s", head, failures, err.Error())
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	return nil
+}
@@ -4,12 +4,17 @@ import (
 	//"github.com/gomodule/redigo/redis"
 	"qoobing.com/utillib.golang/log"
 
+	"github.com/EthereumHD/Scan/src/config"
 	"github.com/EthereumHD/Scan/src/model"
 	"math/big"
 
 	. "github.com/EthereumHD/Scan/src/const"
 	"github.com/EthereumHD/Scan/src/util"
 	"errors"
+	"fmt"
+	"github.com/EthereumHD/EhdChain/common"
+	"github.com/EthereumHD/EhdChain/crypto"
+	"github.com/jinzhu/gorm"
 	"go-web3/dto"
 	"os"
 	"qoobing.com/utillib.golang/gls"
@@ -21,6 +26,8 @@ var logid string
 var c = new(Connect)
 var GLastBlock *dto.Block = nil
 
+var errParentHashNotEqual = errors.New("parent hash not equal")
+
 //func init (
 //
 //)
@@ -32,18 +39,36 @@ func StartSyncLastBlock() {
 	logid = "sync" + util.GetRandomCharacter(4)
 	gls.SetGlsValue("logid", logid)
 
-	//var c = new(Connect)
-	max_block, err := (&model.Block{}).GetMaxBlocNumber(c.Mysql())
-	if err != nil && err.Error() != DATA_NOT_EXIST {
+	//start from the checkpoint in t_sync_state, databases synced before it existed start from the max block
+	state, err := (&model.SyncState{}).FindSyncState(c.Mysql(), model.SYNC_STATE_LIVE)
+	if err == nil {
+		c.SetBlockNow(state.F_height)
+		log.Debugf("Find sync state height:%d,hash:%s,start sync from there.", state.F_height, state.F_hash)
+	} else {
+		max_block, err := (&model.Block{}).GetMaxBlocNumber(c.Mysql())
+		if err != nil && err.Error() != DATA_NOT_EXIST {
+
+			log.Fatalf("GetMaxBlocNumber error:%s", err.Error())
+			os.Exit(0)
 
-		log.Fatalf("GetMaxBlocNumber error:%s", err.Error())
-		os.Exit(0)
+		}
+
+		c.SetBlockNow(max_block - 1)
+		log.Debugf("Find databases sync block height:%d,start sync from there.", max_block-1)
+	}
 
+	if c.GetBlockNOw() >= 0 {
+		if last_block, err := c.Web3().Eth.GetBlockByNumber(big.NewInt(c.GetBlockNOw()), false); err != nil {
+			log.Debugf("Eth.GetBlockByNumber:%d error:%s", c.GetBlockNOw(), err.Error())
+		} else {
+			GLastBlock = last_block
+		}
 	}
 
-	c.SetBlockNow(max_block - 1)
-	log.Debugf("Find databases sync block height:%d,start sync from there.", max_block-1)
+	pipeline = NewPipeline(config.Config().Sync.Workers, config.Config().Sync.Prefetch)
+	go pipeline.Report(time.Second * time.Duration(config.Config().Sync.ReportInterval))
 
+	resubscribe_at := time.Time{}
 	for {
 		msg := make(chan int)
 		go func() {
@@ -60,33 +85,41 @@ func StartSyncLastBlock() {
 
 				if err != nil {
 					log.Debugf("Eth.GetBlockNumber error:%s", err)
+					recordSyncError(err)
 					time.Sleep(time.Second * 5)
 					continue
 				}
 
+				if err = model.UpdateSyncHead(c.Mysql(), model.SYNC_STATE_LIVE, blockNumber.Int64()); err != nil {
+					log.Debugf("UpdateSyncHead error:%s", err.Error())
+				}
+
 				log.Debugf("\n\nEth.GetLastBlock hegiht:%d", blockNumber.Int64())
+				//a chain behind databases is a lagging node or a shorter fork,
+				//the fork is rolled back by rollbackReorg once the chain grows past databases
 				if blockNumber.Int64() < c.GetBlockNOw() {
-
-					log.Fatalf("blockNumber.Int64():%d <BlockNOw:%d,so sync from parent", blockNumber.Int64(), c.GetBlockNOw())
-
-					c.AddBlockNow(-1)
-					DropBlok(c.GetBlockNOw())
-
-					time.Sleep(time.Millisecond * 100)
-					continue
+					log.Debugf("blockNumber.Int64():%d <BlockNOw:%d,wait for chain", blockNumber.Int64(), c.GetBlockNOw())
 				}
 
 				for blockNumber.Int64() > c.GetBlockNOw() {
 
-					err = SyncOneBlock(c.GetBlockNOw() + 1)
+					err = pipeline.Run(c.GetBlockNOw()+1, blockNumber.Int64())
 					if err != nil {
-						log.Debugf("SyncOneBlock error:%s", err.Error())
+						log.Debugf("Pipeline.Run error:%s", err.Error())
+						recordSyncError(err)
 						time.Sleep(time.Millisecond * 100)
 						continue
 					}
+				}
 
-					log.Debugf("SyncOneBlock:%d success", c.GetBlockNOw()+1)
-					c.AddBlockNow(1)
+				//caught up by polling, follow newHeads until the subscription drops
+				if config.Config().Sync.WebSocket != "" && time.Now().After(resubscribe_at) {
+					err = followNewHeads(config.Config().Sync.WebSocket)
+					if err != nil {
+						log.Debugf("followNewHeads error:%s,fall back to polling", err.Error())
+					}
+					resubscribe_at = time.Now().Add(time.Second * time.Duration(config.Config().Sync.ResubscribeInterval))
+					continue
 				}
 
 				time.Sleep(time.Second * 1)
@@ -99,147 +132,266 @@ func StartSyncLastBlock() {
 
 }
 
-func SyncOneBlock(height int64) error {
-	transactions := make(map[string]dto.TransactionResponse)
-	transactionReceipts := make(map[string]dto.TransactionReceipt)
+//recordSyncError count err in t_sync_state, a reorg is counted by rollbackReorg
+func recordSyncError(err error) {
+	if err == errReorganized {
+		return
+	}
 
-	log.Debugf("Start sync block:%d", height)
-	if err := DropBlok(height); err != nil {
+	if err = model.AddSyncError(c.Mysql(), model.SYNC_STATE_LIVE, err); err != nil {
+		log.Debugf("recordSyncError error:%s", err.Error())
+	}
+}
+
+func SyncOneBlock(height int64) error {
+	data, err := FetchBlock(height)
+	if err != nil {
 		return err
 	}
 
-	//1.get block and parent block
+	return CommitBlock(data)
+}
+
+//blockData is everything CommitBlock needs to write one height
+type blockData struct {
+	height       int64
+	head         int64 //head of the chain when the height was fetched, for what is only done near it
+	block        *dto.Block
+	transactions map[string]dto.TransactionResponse
+	receipts     map[string]dto.TransactionReceipt
+	codes        map[string]string         //bytecode of the contracts deployed in the block
+	tokens       map[string]model.Token    //metadata of the tokens transferred in the block, only the fetched ones
+	tokenURIs    map[string]string         //tokenURI of the ERC-721 token ids minted in the block, by token:tokenId
+	traces       map[string]*dto.CallFrame //call trees of the contract transactions, only with the trace stage on
+	traceErrors  map[string]string         //why the trace of a contract transaction failed, it is written without one
+	poc          *dto.Poc                  //proof of the block, nil for the genesis block
+}
+
+//FetchBlock get block, transactions and receipts of height from chain, no database access
+func FetchBlock(height int64) (*blockData, error) {
+	data := &blockData{
+		height:       height,
+		head:         height,
+		transactions: make(map[string]dto.TransactionResponse),
+		receipts:     make(map[string]dto.TransactionReceipt),
+		codes:        make(map[string]string),
+		tokens:       make(map[string]model.Token),
+		tokenURIs:    make(map[string]string),
+		traces:       make(map[string]*dto.CallFrame),
+		traceErrors:  make(map[string]string),
+	}
+
+	log.Debugf("Start fetch block:%d", height)
+
+	//1.get block
 	chain_block, err := c.Web3().Eth.GetBlockByNumber(big.NewInt(height), true)
 	if err != nil {
 		log.Debugf("Eth.GetBlockByNumber:%d error:%s", height, err.Error())
-		return err
+		return nil, err
 	}
-	GLastBlock = chain_block
+	data.block = chain_block
 	log.Debugf("Get chain_block:%d success,hash:%s \n detail:%+v\n", chain_block.Number, chain_block.Hash, chain_block)
 
-	var chain_parent_block *dto.Block
-	if height > 0 {
-		chain_parent_block, err = c.Web3().Eth.GetBlockByNumber(big.NewInt(height-1), true)
-		if err != nil {
-			log.Debugf("Eth.GetBlockByNumber:%d error:%s", height-1, err.Error())
-			return err
+	if err := fetchPoc(data); err != nil {
+		return nil, err
+	}
+
+	//2.get transcations and transreceipts, one batch request for each
+	if len(chain_block.Transactions) == 0 {
+		return data, nil
+	}
+
+	transactions, errs, err := c.Web3().Eth.GetTransactionsByHash(chain_block.Transactions)
+	if err != nil {
+		log.Debugf("Eth.GetTransactionsByHash,block:%d error:%s", height, err.Error())
+		return nil, err
+	}
+	for i, hash := range chain_block.Transactions {
+		if errs[i] != nil {
+			log.Debugf("Eth.GetTransactionsByHash,hash:%s error:%s", hash, errs[i].Error())
+			return nil, errs[i]
+		}
+		data.transactions[hash] = *transactions[i]
+	}
+
+	receipts, errs, err := c.Web3().Eth.GetTransactionReceipts(chain_block.Transactions)
+	if err != nil {
+		log.Debugf("Eth.GetTransactionReceipts,block:%d error:%s", height, err.Error())
+		return nil, err
+	}
+	for i, hash := range chain_block.Transactions {
+		if errs[i] != nil {
+			log.Debugf("Eth.GetTransactionReceipts,hash:%s error:%s", hash, errs[i].Error())
+			return nil, errs[i]
 		}
-		log.Debugf("Get chain_parent_block:%d success,hash:%s ", chain_parent_block.Number, chain_parent_block.Hash)
+		data.receipts[hash] = *receipts[i]
 	}
 
-	//2.get transcations and transreceipts
-	for _, hash := range chain_block.Transactions {
-		transaction, err := c.Web3().Eth.GetTransactionByHash(hash)
+	log.Debugf("Get %d transactions and receipts of block:%d success", len(chain_block.Transactions), height)
+
+	//3.get code of the deployed contracts, at this height in case they selfdestruct later
+	for _, receipt := range data.receipts {
+		if receipt.ContractAddress == "" {
+			continue
+		}
+		address := strings.ToLower(receipt.ContractAddress)
+		code, err := c.Web3().Eth.GetCode(address, fmt.Sprintf("0x%x", height))
 		if err != nil {
-			log.Debugf("Eth.GetTransactionByHash,hash:%s error:%s", hash, err.Error())
+			log.Debugf("Eth.GetCode,address:%s error:%s", address, err.Error())
+			return nil, err
+		}
+		data.codes[address] = code
+	}
+
+	//4.get metadata of the transferred tokens
+	fetchTokens(data)
+	fetchNfts(data)
+
+	//5.trace the internal calls
+	if err := fetchTraces(data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+//CommitBlock check the fetched block against its parent in databases and write it,
+//the fork reset, block, transcations and miner reward of one height are committed in one transaction
+func CommitBlock(data *blockData) error {
+	height := data.height
+	chain_block := data.block
+
+	log.Debugf("Start sync block:%d", height)
+	err := model.InTransaction(c.Mysql(), func(tx *gorm.DB) error {
+		if err := dropBlock(tx, height); err != nil {
 			return err
 		}
 
-		receipt, err := c.Web3().Eth.GetTransactionReceipt(hash)
-		if err != nil {
-			log.Debugf("Eth.GetTransactionReceipt ,hash:%s error:%s", hash, err.Error())
+		//3.check parent block
+		databases_block_parent, err := (&model.Block{}).FindBlockByHeight(tx, height-1)
+		if err != nil && err.Error() != DATA_NOT_EXIST {
+			log.Debugf("FindBlockByHeight ,height:%d error:%s", height, err.Error())
 			return err
 		}
 
-		transactions[hash] = *transaction
-		transactionReceipts[hash] = *receipt
+		if err != nil && err.Error() == DATA_NOT_EXIST && height != 0 {
+			c.AddBlockNow(-1)
+			log.Debugf("FindBlockByHeight,height:%d ,DATA_NOT_EXIST,sync from parent_block", height)
+			return err
+		}
 
-		log.Debugf("Get %s  transaction and receipt success, ", hash)
-		log.Debugf("transaction:%+v", *transaction)
-		log.Debugf("transactionReceipts:%+v", *receipt)
-	}
+		if height > 0 && chain_block.ParentHash != databases_block_parent.F_hash {
+			log.Debugf("chain_block parent hash:%s,databases_block_parent hash:%s,not equal,roll back to ancestor",
+				chain_block.ParentHash, databases_block_parent.F_hash)
+			return errParentHashNotEqual
+		}
 
-	//3.check parent block
-	databases_block_parent, err := (&model.Block{}).FindBlockByHeight(c.Mysql(), height-1)
-	if err != nil && err.Error() != DATA_NOT_EXIST {
-		log.Debugf("FindBlockByHeight ,height:%d error:%s", height, err.Error())
-		return err
-	}
+		//4.write block
+		if err := writeBlockData(tx, data); err != nil {
+			return err
+		}
 
-	if err != nil && err.Error() == DATA_NOT_EXIST && height != 0 {
-		c.AddBlockNow(-1)
-		log.Debugf("FindBlockByHeight,height:%d ,DATA_NOT_EXIST,sync from parent_block", height)
+		//5.move the checkpoint with the block
+		err = model.UpdateSyncHeight(tx, model.SYNC_STATE_LIVE, height, chain_block.Hash)
+		if err != nil {
+			log.Debugf("UpdateSyncHeight:%d failed", height)
+			return err
+		}
+
+		return nil
+	})
+	if err == errParentHashNotEqual {
+		return rollbackReorg(chain_block)
+	}
+	if err != nil {
 		return err
 	}
+	GLastBlock = chain_block
+	rememberTokens(data)
+	snapshotSummary(height)
 
-	if height > 0 && chain_parent_block.Hash != databases_block_parent.F_hash {
-		c.AddBlockNow(-1)
-		log.Debugf("chain_parent_block hash:%s,databases_block_parent hash:%s,not equal,sync from parent_block",
-			chain_parent_block.Hash, databases_block_parent.F_hash)
-		return errors.New("parent hash not equal")
-	}
+	//todo add map[miner]miner to recount miner reward there .
 
-	//4.write block
-	err = WriteBlock(c, *chain_block, transactions, transactionReceipts)
+	return nil
+}
+
+//writeBlockData write everything fetched for one height, shared by the live syncer and backfill
+func writeBlockData(db *gorm.DB, data *blockData) error {
+	chain_block := data.block
+
+	err := WriteBlock(db, *chain_block, data.transactions, data.receipts)
 	if err != nil {
 		log.Debugf("WriteBlock:%s failed", chain_block.Hash)
 		return err
 	}
+	//write poc
+	err = WriteBlockPoc(db, *chain_block, data.poc)
+	if err != nil {
+		log.Debugf("WriteBlockPoc:%d failed", chain_block.Number.Int64())
+		return err
+	}
 	//write transcations
-	err = WriteTransactions(c, *chain_block, transactions, transactionReceipts)
+	err = WriteTransactions(db, *chain_block, data.transactions, data.receipts)
 	if err != nil {
 		log.Debugf("WriteTransactions:%d failed", chain_block.Number.Int64())
 		return err
 	}
-
-	//todo add map[miner]miner to recount miner reward there .
+	//write logs
+	err = WriteLogs(db, *chain_block, data.receipts)
+	if err != nil {
+		log.Debugf("WriteLogs:%d failed", chain_block.Number.Int64())
+		return err
+	}
+	//write contracts
+	err = WriteContracts(db, *chain_block, data.transactions, data.receipts, data.codes)
+	if err != nil {
+		log.Debugf("WriteContracts:%d failed", chain_block.Number.Int64())
+		return err
+	}
+	//write token transfers and holder balances
+	err = WriteTokenTransfers(db, *chain_block, data.receipts, data.tokens)
+	if err != nil {
+		log.Debugf("WriteTokenTransfers:%d failed", chain_block.Number.Int64())
+		return err
+	}
+	//write ERC-721 transfers and owners
+	err = WriteNftTransfers(db, *chain_block, data.receipts, data.tokens, data.tokenURIs)
+	if err != nil {
+		log.Debugf("WriteNftTransfers:%d failed", chain_block.Number.Int64())
+		return err
+	}
+	//write mortgage ledger
+	err = WriteMortgages(db, *chain_block, data.transactions, data.receipts)
+	if err != nil {
+		log.Debugf("WriteMortgages:%d failed", chain_block.Number.Int64())
+		return err
+	}
+	//write internal calls
+	err = WriteInternalTxs(db, *chain_block, data.transactions, data.traces, data.traceErrors)
+	if err != nil {
+		log.Debugf("WriteInternalTxs:%d failed", chain_block.Number.Int64())
+		return err
+	}
+	//add the block to the day and the hour of its miner
+	err = addStats(db, data)
+	if err != nil {
+		log.Debugf("addStats:%d failed", chain_block.Number.Int64())
+		return err
+	}
 
 	return nil
 }
 
-func WriteTransactions(c *Connect, chain_block dto.Block, transactions map[string]dto.TransactionResponse, receipts map[string]dto.TransactionReceipt) error {
-
-	////1.find trans by height
-	//old_transcations, err := (&model.Transaction{}).FindTrasactionByHeight(c.Mysql(), chain_block.Number.Int64())
-	//
-	//if err != nil {
-	//	if err.Error() != DATA_NOT_EXIST {
-	//		fatal_list("FindTrasactionByHeight:%d error:%s", chain_block.Number.Int64(), err.Error())
-	//		return err
-	//	}
-	//} else {
-	//	log.Debugf("Find old_transcations ,height:%d", chain_block.Number.Int64())
-	//
-	//	for _, trans := range old_transcations {
-	//		if trans.F_status == NORMAL {
-	//			trans.F_status = FORK
-	//			err = trans.UpdateTransactionStatus(c.Mysql())
-	//			if err != nil {
-	//				fatal_list("UpdateTransactionStatus:%s error:%s", trans.F_tx_hash, err.Error())
-	//				return err
-	//			}
-	//
-	//			log.Debugf("UpdateTransactionStatus :%s success", trans.F_tx_hash)
-	//		}
-	//	}
-	//
-	//}
+func WriteTransactions(db *gorm.DB, chain_block dto.Block, transactions map[string]dto.TransactionResponse, receipts map[string]dto.TransactionReceipt) error {
 
-	//height
+	//all transcations of the block go to databases in bulk,
+	//the ones already there (forked before) are moved to this block and set NORMAL
+	databases_transactions := make([]model.Transaction, 0, len(transactions))
 	for tx_hash, transaction := range transactions {
-		databases_trans, err := (&model.Transaction{}).FindTrasactionByHash(c.Mysql(), tx_hash)
-		if err != nil {
-			if err.Error() != DATA_NOT_EXIST {
-				log.Debugf("FindTrasactionByHash:%s error:%s", tx_hash, err.Error())
-				return err
-			}
-		} else {
-			if databases_trans.F_status != NORMAL {
-				databases_trans.F_status = NORMAL
-				err = databases_trans.UpdateTransactionStatus(c.Mysql())
-				if err != nil {
-					log.Debugf("UpdateTransactionStatus:%s error:%s", tx_hash, err.Error())
-					return err
-				}
-
-				log.Debugf("UpdateTransactionStatus :%s success", tx_hash)
-				continue
-			}
-		}
-
 		receipt := receipts[tx_hash]
 		tx_fee := big.NewInt(0).Mul(transaction.GasPrice, receipt.GasUsed)
 
+		databases_trans := model.Transaction{}
 		databases_trans.F_tx_hash = tx_hash
 		databases_trans.F_block = chain_block.Number.Int64()
 		databases_trans.F_timestamp = chain_block.Timestamp.Int64()
@@ -249,37 +401,115 @@ func WriteTransactions(c *Connect, chain_block dto.Block, transactions map[strin
 		databases_trans.F_tx_fee = tx_fee.String()
 		databases_trans.F_status = NORMAL
 		databases_trans.F_tx_type, databases_trans.F_tx_type_ext = CalcTransactionType(transaction)
+		databases_trans.F_nonce = transaction.Nonce.Int64()
+		databases_trans.F_tx_index = transaction.TransactionIndex.Int64()
+		databases_trans.F_gas = transaction.Gas.String()
+		databases_trans.F_gas_price = transaction.GasPrice.String()
+		databases_trans.F_gas_used = receipt.GasUsed.String()
+		databases_trans.F_input = transaction.Input
+		databases_trans.F_receipt_status = int(receipt.Status.Int64())
+		databases_trans.F_contract_address = strings.ToLower(receipt.ContractAddress)
+
+		databases_transactions = append(databases_transactions, databases_trans)
+	}
 
-		err = databases_trans.CreateTransaction(c.mysql)
-		if err != nil {
-			log.Debugf("CreateTransaction:%s error:%s", tx_hash, err.Error())
-			return err
+	err := model.CreateTransactions(db, databases_transactions)
+	if err != nil {
+		log.Debugf("CreateTransactions,block:%d error:%s", chain_block.Number.Int64(), err.Error())
+		return err
+	}
+
+	log.Debugf("CreateTransactions success,block:%d,num:%d", chain_block.Number.Int64(), len(databases_transactions))
+
+	return nil
+}
+
+func WriteLogs(db *gorm.DB, chain_block dto.Block, receipts map[string]dto.TransactionReceipt) error {
+
+	databases_logs := make([]model.Log, 0)
+	for _, receipt := range receipts {
+		for _, chain_log := range receipt.Logs {
+			databases_log := model.Log{}
+			databases_log.F_block = chain_block.Number.Int64()
+			databases_log.F_block_hash = chain_block.Hash
+			databases_log.F_timestamp = chain_block.Timestamp.Int64()
+			databases_log.F_tx_hash = receipt.TransactionHash
+			databases_log.F_tx_index = chain_log.TransactionIndex.Int64()
+			databases_log.F_log_index = chain_log.LogIndex.Int64()
+			databases_log.F_address = strings.ToLower(chain_log.Address)
+			databases_log.F_data = chain_log.Data
+
+			topics := []*string{&databases_log.F_topic0, &databases_log.F_topic1, &databases_log.F_topic2, &databases_log.F_topic3}
+			for i, topic := range chain_log.Topics {
+				if i < len(topics) {
+					*topics[i] = strings.ToLower(topic)
+				}
+			}
+
+			databases_logs = append(databases_logs, databases_log)
 		}
+	}
 
-		log.Debugf("CreateTransaction success:%+v", databases_trans)
+	err := model.CreateLogs(db, databases_logs)
+	if err != nil {
+		log.Debugf("CreateLogs,block:%d error:%s", chain_block.Number.Int64(), err.Error())
+		return err
 	}
 
+	log.Debugf("CreateLogs success,block:%d,num:%d", chain_block.Number.Int64(), len(databases_logs))
+
+	return nil
+}
+
+func WriteContracts(db *gorm.DB, chain_block dto.Block, transactions map[string]dto.TransactionResponse,
+	receipts map[string]dto.TransactionReceipt, codes map[string]string) error {
+
+	databases_contracts := make([]model.Contract, 0)
+	for tx_hash, receipt := range receipts {
+		//a failed deployment reports the address but leaves no code
+		if receipt.ContractAddress == "" || (receipt.Status != nil && receipt.Status.Int64() == 0) {
+			continue
+		}
+
+		address := strings.ToLower(receipt.ContractAddress)
+		code := common.FromHex(codes[address])
+
+		databases_contract := model.Contract{}
+		databases_contract.F_address = address
+		databases_contract.F_creator = strings.ToLower(transactions[tx_hash].From)
+		databases_contract.F_tx_hash = tx_hash
+		databases_contract.F_block = chain_block.Number.Int64()
+		databases_contract.F_timestamp = chain_block.Timestamp.Int64()
+		databases_contract.F_code_hash = crypto.Keccak256Hash(code).Hex()
+		databases_contract.F_code_size = int64(len(code))
+
+		databases_contracts = append(databases_contracts, databases_contract)
+	}
+
+	err := model.CreateContracts(db, databases_contracts)
+	if err != nil {
+		log.Debugf("CreateContracts,block:%d error:%s", chain_block.Number.Int64(), err.Error())
+		return err
+	}
+
+	log.Debugf("CreateContracts success,block:%d,num:%d", chain_block.Number.Int64(), len(databases_contracts))
+
 	return nil
 }
 
 func CalcTransactionType(transaction dto.TransactionResponse) (txtype int64, txtypeext string) {
 	log.Debugf("transaction.To=%s,Input=%s,MORTGAGECONTRACTADDR=%s,MORTGAGECONTRACT_FUNC_MORTGAGE=%s",
 		transaction.To, transaction.Input, MORTGAGECONTRACTADDR, MORTGAGECONTRACT_FUNC_MORTGAGE)
-	if transaction.To == MORTGAGECONTRACTADDR {
-		if strings.HasPrefix(transaction.Input, MORTGAGECONTRACT_FUNC_MORTGAGE) {
-			txtype = model.TX_TYPE_ME_MORTGAGE
-			txtypeext = "0x" + transaction.Input[len(transaction.Input)-64:]
-		} else if strings.HasPrefix(transaction.Input, MORTGAGECONTRACT_FUNC_REDEEM) {
-			txtype = model.TX_TYPE_ME_REDEEM
-			txtypeext = "0x" + transaction.Input[len(transaction.Input)-64:]
-		}
+	if mortgage_type, _, amount, ok := decodeMortgage(transaction); ok {
+		txtype = mortgage_type
+		txtypeext = fmt.Sprintf("0x%064x", amount)
 	}
 	return
 }
 
-func WriteBlock(c *Connect, chain_block dto.Block, transactions map[string]dto.TransactionResponse, receipts map[string]dto.TransactionReceipt) (err error) {
+func WriteBlock(db *gorm.DB, chain_block dto.Block, transactions map[string]dto.TransactionResponse, receipts map[string]dto.TransactionReceipt) (err error) {
 	//1.find old block
-	databases_block, err := (&model.Block{}).FindBlockByHash(c.Mysql(), chain_block.Hash)
+	databases_block, err := (&model.Block{}).FindBlockByHash(db, chain_block.Hash)
 	if err != nil {
 		if err.Error() != DATA_NOT_EXIST {
 			log.Debugf("FindBlockByHash:%s error:%s", chain_block.Hash, err.Error())
@@ -290,7 +520,7 @@ func WriteBlock(c *Connect, chain_block dto.Block, transactions map[string]dto.T
 
 		if databases_block.F_status != NORMAL {
 			databases_block.F_status = NORMAL
-			err = databases_block.UpdateBlockStatus(c.Mysql())
+			err = databases_block.UpdateBlockStatus(db)
 			if err != nil {
 				log.Debugf("UpdateBlockStatus:%s error:%s", chain_block.Hash, err.Error())
 				return err
@@ -298,10 +528,27 @@ func WriteBlock(c *Connect, chain_block dto.Block, transactions map[string]dto.T
 			log.Debugf("UpdateBlockStatus sucess,block hash:%s", chain_block.Hash)
 		}
 
+		//synced by an older version, fill the detail columns
+		if !databases_block.HasDetail() {
+			setBlockDetail(&databases_block, chain_block)
+			err = databases_block.UpdateBlockDetail(db)
+			if err != nil {
+				log.Debugf("UpdateBlockDetail:%s error:%s", chain_block.Hash, err.Error())
+				return err
+			}
+		}
+
+		//credit it again if it was forked
+		_, err = settleBlockReward(db, databases_block)
+		if err != nil {
+			log.Debugf("settleBlockReward:%s error:%s", chain_block.Hash, err.Error())
+			return err
+		}
+
 		return nil
 	}
 
-	//databases_block, err = (&model.Block{}).FindBlockByHeight(c.Mysql(), chain_block.Number.Int64())
+	//databases_block, err = (&model.Block{}).FindBlockByHeight(db, chain_block.Number.Int64())
 	//if err != nil {
 	//	if err.Error() != DATA_NOT_EXIST {
 	//		fatal_list("FindBlockByHeight:%d error:%s", chain_block.Number.Int64(), err.Error())
@@ -311,7 +558,7 @@ func WriteBlock(c *Connect, chain_block dto.Block, transactions map[string]dto.T
 	//	log.Debugf("FindBlockByHeight:%d", chain_block.Number.Int64())
 	//
 	//	databases_block.F_status = FORK
-	//	err = databases_block.UpdateBlockStatus(c.Mysql())
+	//	err = databases_block.UpdateBlockStatus(db)
 	//	if err != nil {
 	//		fatal_list("UpdateBlockStatus:%s error:%s", chain_block.Hash, err.Error())
 	//		return err
@@ -343,16 +590,17 @@ func WriteBlock(c *Connect, chain_block dto.Block, transactions map[string]dto.T
 	databases_block.F_reward = chain_block.Reward.String()
 	databases_block.F_fees = fees.String()
 	databases_block.F_status = NORMAL
+	setBlockDetail(&databases_block, chain_block)
 
-	err = databases_block.CreateBlock(c.Mysql())
+	err = databases_block.CreateBlock(db)
 	if err != nil {
 		log.Debugf("CreateBlock:%d error:%s", chain_block.Number.Int64(), err.Error())
 		return err
 	}
 
-	err = WriteMinerRewards(c, chain_block.Miner, chain_block.Reward, fees)
+	_, err = settleBlockReward(db, databases_block)
 	if err != nil {
-		log.Debugf("WriteMinerRewards:%s error:%s", chain_block.Miner, err.Error())
+		log.Debugf("settleBlockReward:%s error:%s", chain_block.Hash, err.Error())
 		return err
 	}
 
@@ -361,32 +609,10 @@ func WriteBlock(c *Connect, chain_block dto.Block, transactions map[string]dto.T
 	return nil
 }
 
-func WriteMinerRewards(c *Connect, miner string, reward *big.Int, fees *big.Int) error {
-	log.Debugf("WriteMinerRewards,miner:%s,reward:%d, fes:%d", miner, reward, fees)
-	miner_reward, err := (&model.MinerReward{}).FindRewardByMiner(c.Mysql(), miner)
-	if err != nil {
-		if err.Error() == DATA_NOT_EXIST {
-			newMinerReward := &model.MinerReward{
-				F_miner:        miner,
-				F_total_reward: reward.String(),
-				F_total_fees:   fees.String()}
-			err := newMinerReward.CreateMinerReward(c.Mysql())
-			return err
-		}
-		return err
-	}
-
-	total_old := big.NewInt(0)
-	total_old.SetString(miner_reward.F_total_reward, 10)
-	total := big.NewInt(0).Add(total_old, reward)
-	miner_reward.F_total_reward = total.String()
-
-	total_fees_old := big.NewInt(0)
-	total_fees_old.SetString(miner_reward.F_total_fees, 10)
-	total_fees := big.NewInt(0).Add(total_old, fees)
-	miner_reward.F_total_fees = total_fees.String()
-
-	err = miner_reward.UpdateMinerReward(c.Mysql())
-	return err
-
+func setBlockDetail(databases_block *model.Block, chain_block dto.Block) {
+	databases_block.F_difficulty = chain_block.Difficulty.String()
+	databases_block.F_total_difficulty = chain_block.TotalDifficult.String()
+	databases_block.F_size = chain_block.Size.Int64()
+	databases_block.F_nonce = chain_block.Nonce.String()
+	databases_block.F_extra_data = chain_block.ExtraData
 }
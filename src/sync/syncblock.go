@@ -4,18 +4,26 @@ import (
 	. "github.com/EthereumHD/Scan/src/const"
 	"github.com/EthereumHD/Scan/src/model"
 
-	"math/big"
+	"github.com/jinzhu/gorm"
 	"qoobing.com/utillib.golang/log"
 )
 
+//DropBlok set the block of height and everything in it to FORK in one transaction
 func DropBlok(height int64) error {
+	return model.InTransaction(c.Mysql(), func(tx *gorm.DB) error {
+		return dropBlock(tx, height)
+	})
+}
+
+func dropBlock(db *gorm.DB, height int64) error {
 
-	if height <= 1 {
+	//the genesis block is never forked, a reorg may reach block 1
+	if height <= 0 {
 		return nil
 	}
 
 	//find block ,reset
-	block, err := (&model.Block{}).FindBlockByHeight(c.Mysql(), height)
+	block, err := (&model.Block{}).FindBlockByHeight(db, height)
 	if err != nil {
 		if err.Error() != DATA_NOT_EXIST {
 			log.Debugf("FindBlockByHeight,error:%s", err.Error())
@@ -27,71 +35,84 @@ func DropBlok(height int64) error {
 	log.Debugf("Find fork block:%d,hash:%s", height, block.F_hash)
 
 	block.F_status = FORK
-	err = block.UpdateBlockStatus(c.Mysql())
+	err = block.UpdateBlockStatus(db)
 	if err != nil {
 		log.Debugf("UpdateBlockStatus,error:%s", err.Error())
 		return err
 	}
 
-	//find transaction ,reset
-	transactions, err := (&model.Transaction{}).FindTrasactionByHeight(c.Mysql(), height)
+	err = model.UpdateTransactionStatusByHeight(db, height, FORK)
 	if err != nil {
-		if err.Error() != DATA_NOT_EXIST {
-			log.Debugf("FindTrasactionByHeight,error:%s", err.Error())
-			return err
-		}
+		log.Debugf("UpdateTransactionStatusByHeight,error:%s", err.Error())
+		return err
 	}
-	log.Debugf("Find old transacions:%d,num:%d", height, len(transactions))
 
-	block_fees := big.NewInt(0)
-	block_reward, b := big.NewInt(0).SetString(block.F_reward, 10)
-	if b == false {
-		log.Debugf("big.NewInt(0).SetString,fale")
+	err = model.UpdateLogStatusByHeight(db, height, FORK)
+	if err != nil {
+		log.Debugf("UpdateLogStatusByHeight,error:%s", err.Error())
 		return err
 	}
 
-	for _, transaction := range transactions {
-		tx_fee, b := big.NewInt(0).SetString(transaction.F_tx_fee, 10)
-		if b == false {
-			log.Debugf("big.NewInt(0).SetString,fale")
-			return err
-		}
-		block_fees.Add(block_fees, tx_fee)
+	err = model.UpdateContractStatusByHeight(db, height, FORK)
+	if err != nil {
+		log.Debugf("UpdateContractStatusByHeight,error:%s", err.Error())
+		return err
+	}
+
+	err = model.UpdateBlockPocStatusByHeight(db, height, FORK)
+	if err != nil {
+		log.Debugf("UpdateBlockPocStatusByHeight,error:%s", err.Error())
+		return err
+	}
+
+	err = model.UpdateSummaryHistoryStatusByHeight(db, height, FORK)
+	if err != nil {
+		log.Debugf("UpdateSummaryHistoryStatusByHeight,error:%s", err.Error())
+		return err
+	}
 
-		transaction.F_status = FORK
-		transaction.UpdateTransactionStatus(c.Mysql())
+	err = model.UpdateMortgageStatusByHeight(db, height, FORK)
+	if err != nil {
+		log.Debugf("UpdateMortgageStatusByHeight,error:%s", err.Error())
+		return err
 	}
 
-	//find block_reward
-	miner_reward, err := (&model.MinerReward{}).FindRewardByMiner(c.Mysql(), block.F_miner)
+	err = model.UpdateInternalTxStatusByHeight(db, height, FORK)
 	if err != nil {
-		log.Debugf("FindRewardByMiner,error:%s", err.Error())
+		log.Debugf("UpdateInternalTxStatusByHeight,error:%s", err.Error())
 		return err
 	}
 
-	reward, b := big.NewInt(0).SetString(miner_reward.F_total_reward, 10)
-	if b == false {
-		log.Debugf("big.NewInt(0).SetString,fale")
+	err = model.UpdateTraceMissingStatusByHeight(db, height, FORK)
+	if err != nil {
+		log.Debugf("UpdateTraceMissingStatusByHeight,error:%s", err.Error())
 		return err
 	}
 
-	fee, b := big.NewInt(0).SetString(miner_reward.F_total_fees, 10)
-	if b == false {
-		log.Debugf("big.NewInt(0).SetString,fale")
+	err = dropTokenTransfers(db, height)
+	if err != nil {
+		log.Debugf("dropTokenTransfers,error:%s", err.Error())
 		return err
 	}
 
-	log.Debugf("Drop example,height:%d,old:%s,block_reward:%s", height, reward.String(), block_reward)
-	reward.Sub(reward, block_reward)
-	miner_reward.F_total_reward = reward.String()
-	fee.Sub(reward, block_fees)
-	miner_reward.F_total_fees = fee.String()
+	err = dropNftTransfers(db, height)
+	if err != nil {
+		log.Debugf("dropNftTransfers,error:%s", err.Error())
+		return err
+	}
 
-	miner_reward.UpdateMinerReward(c.Mysql())
+	err = refreshStats(db, block.F_timestamp)
+	if err != nil {
+		log.Debugf("refreshStats,error:%s", err.Error())
+		return err
+	}
 
-	log.Debugf("Drop example,height:%d,block_reward_new:%s", height, reward.String())
-	//time.Sleep(time.Second*10)
+	//reverse the reward of the block, the miner totals follow the ledger
+	_, err = settleBlockReward(db, block)
+	if err != nil {
+		log.Debugf("settleBlockReward,error:%s", err.Error())
+		return err
+	}
 
 	return nil
-
 }
@@ -0,0 +1,300 @@
+package sync
+
+import (
+	"fmt"
+	"github.com/EthereumHD/EhdChain/common"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"go-web3/complex/types"
+	"go-web3/dto"
+	"math/big"
+	"qoobing.com/utillib.golang/log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// knownTokens the tokens whose metadata was committed by this process, the value is unused
+var knownTokens sync.Map
+
+// tokenTransfer is an ERC-20 Transfer log of a block
+type tokenTransfer struct {
+	token    string
+	from     string
+	to       string
+	value    *big.Int
+	txHash   string
+	logIndex int64
+}
+
+// decodeTokenTransfer decode an ERC-20 Transfer(address indexed,address indexed,uint256) log,
+// ERC-721 Transfer has the same topic0 but an indexed tokenId, so 4 topics and no data
+func decodeTokenTransfer(chain_log dto.TransactionLogs) (transfer tokenTransfer, ok bool) {
+	if len(chain_log.Topics) != 3 || strings.ToLower(chain_log.Topics[0]) != TOKEN_TRANSFER_TOPIC {
+		return transfer, false
+	}
+
+	data := common.FromHex(chain_log.Data)
+	if len(data) != 32 {
+		return transfer, false
+	}
+
+	transfer.token = strings.ToLower(chain_log.Address)
+	transfer.from = topicToAddress(chain_log.Topics[1])
+	transfer.to = topicToAddress(chain_log.Topics[2])
+	transfer.value = big.NewInt(0).SetBytes(data)
+	transfer.txHash = chain_log.TransactionHash
+	transfer.logIndex = chain_log.LogIndex.Int64()
+	return transfer, true
+}
+
+// decodeTokenTransfers all ERC-20 transfers of the receipts
+func decodeTokenTransfers(receipts map[string]dto.TransactionReceipt) []tokenTransfer {
+	transfers := make([]tokenTransfer, 0)
+	for _, receipt := range receipts {
+		for _, chain_log := range receipt.Logs {
+			if transfer, ok := decodeTokenTransfer(chain_log); ok {
+				transfers = append(transfers, transfer)
+			}
+		}
+	}
+	return transfers
+}
+
+func topicToAddress(topic string) string {
+	return strings.ToLower(common.BytesToAddress(common.FromHex(topic)).Hex())
+}
+
+// fetchTokens get the metadata of the tokens not committed by this process yet, and the totalSupply of
+// the ones minted or burned in the block. Blocks prefetched together may fetch a new token more than once
+func fetchTokens(data *blockData) {
+	for _, transfer := range decodeTokenTransfers(data.receipts) {
+		if _, ok := data.tokens[transfer.token]; ok {
+			continue
+		}
+
+		_, known := knownTokens.Load(transfer.token)
+		if known && transfer.from != ZEROADDR && transfer.to != ZEROADDR {
+			continue
+		}
+
+		token := model.Token{F_address: transfer.token, F_type: TOKEN_TYPE_ERC20, F_decimals: -1, F_supply_block: -1}
+		if !known {
+			token.F_name = decodeStringResult(callToken(transfer.token, data.height, TOKEN_FUNC_NAME))
+			token.F_symbol = decodeStringResult(callToken(transfer.token, data.height, TOKEN_FUNC_SYMBOL))
+			if decimals := decodeUintResult(callToken(transfer.token, data.height, TOKEN_FUNC_DECIMALS)); decimals != nil && decimals.BitLen() <= 8 {
+				token.F_decimals = int(decimals.Int64())
+			}
+		}
+		if supply := decodeUintResult(callToken(transfer.token, data.height, TOKEN_FUNC_TOTALSUPPLY)); supply != nil {
+			token.F_total_supply = supply.String()
+			token.F_supply_block = data.height
+		}
+		data.tokens[transfer.token] = token
+	}
+}
+
+// rememberTokens called after the block is committed, its tokens' metadata is in databases
+func rememberTokens(data *blockData) {
+	for address := range data.tokens {
+		knownTokens.Store(address, true)
+	}
+}
+
+// callToken eth_call a no argument function of token at height, so a backfilled block records the
+// totalSupply of its own time, nil if the contract does not implement it
+func callToken(token string, height int64, function string) []byte {
+	result, err := c.Web3().Eth.CallAt(&dto.TransactionParameters{To: token, Data: types.ComplexString(function)},
+		fmt.Sprintf("0x%x", height))
+	if err != nil {
+		log.Debugf("Eth.Call,token:%s,function:%s error:%s", token, function, err.Error())
+		return nil
+	}
+
+	ret, err := result.ToString()
+	if err != nil {
+		log.Debugf("Eth.Call,token:%s,function:%s error:%s", token, function, err.Error())
+		return nil
+	}
+
+	return common.FromHex(ret)
+}
+
+// decodeStringResult decode an abi encoded string, or the bytes32 returned by some early tokens
+func decodeStringResult(ret []byte) string {
+	if len(ret) == 32 {
+		return strings.TrimRight(string(ret), "\x00")
+	}
+	if len(ret) < 64 {
+		return ""
+	}
+
+	offset := big.NewInt(0).SetBytes(ret[:32])
+	if !offset.IsInt64() || offset.Int64()+32 > int64(len(ret)) {
+		return ""
+	}
+	start := offset.Int64() + 32
+	length := big.NewInt(0).SetBytes(ret[start-32 : start])
+	if !length.IsInt64() || start+length.Int64() > int64(len(ret)) {
+		return ""
+	}
+
+	return string(ret[start : start+length.Int64()])
+}
+
+func decodeUintResult(ret []byte) *big.Int {
+	if len(ret) < 32 {
+		return nil
+	}
+	return big.NewInt(0).SetBytes(ret[:32])
+}
+
+// tokenValueCountable whether the transfer goes into t_token_balance, a decimal(65,0) column
+func tokenValueCountable(value string) bool {
+	return len(value) <= MAXTOKENBALANCEDIGITS
+}
+
+// addTokenBalances apply the transfers to the holder balances, sign -1 to roll them back
+func addTokenBalances(db *gorm.DB, transfers []model.TokenTransfer, sign int64) error {
+	deltas := make(map[[2]string]*big.Int)
+	for _, transfer := range transfers {
+		if !tokenValueCountable(transfer.F_value) {
+			log.Noticef("token transfer %s:%d value:%s too large, not counted in balance",
+				transfer.F_tx_hash, transfer.F_log_index, transfer.F_value)
+			continue
+		}
+		value, b := big.NewInt(0).SetString(transfer.F_value, 10)
+		if b == false {
+			log.Debugf("big.NewInt(0).SetString,fale")
+			continue
+		}
+		value.Mul(value, big.NewInt(sign))
+
+		for _, holder := range []struct {
+			addr  string
+			delta *big.Int
+		}{{transfer.F_from, big.NewInt(0).Neg(value)}, {transfer.F_to, value}} {
+			//mint and burn
+			if holder.addr == ZEROADDR {
+				continue
+			}
+			key := [2]string{transfer.F_token, holder.addr}
+			if deltas[key] == nil {
+				deltas[key] = big.NewInt(0)
+			}
+			deltas[key].Add(deltas[key], holder.delta)
+		}
+	}
+
+	balances := make([]model.TokenBalance, 0, len(deltas))
+	for key, delta := range deltas {
+		if delta.Sign() == 0 {
+			continue
+		}
+		balances = append(balances, model.TokenBalance{F_token: key[0], F_holder: key[1], F_balance: delta.String()})
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].F_token != balances[j].F_token {
+			return balances[i].F_token < balances[j].F_token
+		}
+		return balances[i].F_holder < balances[j].F_holder
+	})
+
+	return model.AddTokenBalances(db, balances)
+}
+
+func WriteTokenTransfers(db *gorm.DB, chain_block dto.Block, receipts map[string]dto.TransactionReceipt, tokens map[string]model.Token) error {
+	height := chain_block.Number.Int64()
+
+	//the transfers already NORMAL (block written again by backfill) are counted in balances
+	counted, err := model.FindTokenTransfersByHeight(db, height)
+	if err != nil {
+		log.Debugf("FindTokenTransfersByHeight:%d error:%s", height, err.Error())
+		return err
+	}
+	countedIndex := make(map[int64]bool)
+	for _, transfer := range counted {
+		if transfer.F_block_hash == chain_block.Hash {
+			countedIndex[transfer.F_log_index] = true
+		}
+	}
+
+	databases_transfers := make([]model.TokenTransfer, 0)
+	uncounted := make([]model.TokenTransfer, 0)
+	databases_tokens := make([]model.Token, 0)
+	seen := make(map[string]bool)
+	for _, transfer := range decodeTokenTransfers(receipts) {
+		databases_transfer := model.TokenTransfer{}
+		databases_transfer.F_block = height
+		databases_transfer.F_block_hash = chain_block.Hash
+		databases_transfer.F_timestamp = chain_block.Timestamp.Int64()
+		databases_transfer.F_tx_hash = transfer.txHash
+		databases_transfer.F_log_index = transfer.logIndex
+		databases_transfer.F_token = transfer.token
+		databases_transfer.F_from = transfer.from
+		databases_transfer.F_to = transfer.to
+		databases_transfer.F_value = transfer.value.String()
+
+		databases_transfers = append(databases_transfers, databases_transfer)
+		if !countedIndex[transfer.logIndex] {
+			uncounted = append(uncounted, databases_transfer)
+		}
+
+		if !seen[transfer.token] {
+			seen[transfer.token] = true
+			token, ok := tokens[transfer.token]
+			if !ok {
+				token = model.Token{F_address: transfer.token, F_type: TOKEN_TYPE_ERC20, F_decimals: -1, F_supply_block: -1}
+			}
+			token.F_block = height
+			databases_tokens = append(databases_tokens, token)
+		}
+	}
+	if len(databases_transfers) == 0 {
+		return nil
+	}
+
+	sort.Slice(databases_tokens, func(i, j int) bool { return databases_tokens[i].F_address < databases_tokens[j].F_address })
+	err = model.CreateTokens(db, databases_tokens)
+	if err != nil {
+		log.Debugf("CreateTokens,block:%d error:%s", height, err.Error())
+		return err
+	}
+
+	err = model.CreateTokenTransfers(db, databases_transfers)
+	if err != nil {
+		log.Debugf("CreateTokenTransfers,block:%d error:%s", height, err.Error())
+		return err
+	}
+
+	err = addTokenBalances(db, uncounted, 1)
+	if err != nil {
+		log.Debugf("addTokenBalances,block:%d error:%s", height, err.Error())
+		return err
+	}
+
+	log.Debugf("CreateTokenTransfers success,block:%d,num:%d", height, len(databases_transfers))
+
+	return nil
+}
+
+// dropTokenTransfers take the NORMAL transfers of height out of the balances and set them FORK
+func dropTokenTransfers(db *gorm.DB, height int64) error {
+	transfers, err := model.FindTokenTransfersByHeight(db, height)
+	if err != nil {
+		log.Debugf("FindTokenTransfersByHeight,error:%s", err.Error())
+		return err
+	}
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	err = addTokenBalances(db, transfers, -1)
+	if err != nil {
+		log.Debugf("addTokenBalances,error:%s", err.Error())
+		return err
+	}
+
+	return model.UpdateTokenTransferStatusByHeight(db, height, FORK)
+}
@@ -0,0 +1,137 @@
+package sync
+
+import (
+	"github.com/EthereumHD/Scan/src/config"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"go-web3/dto"
+	"qoobing.com/utillib.golang/log"
+	"sort"
+	"strings"
+)
+
+// fetchTraces trace the transactions with input (contract calls and creations) when the trace stage is on.
+// The stage is optional: a failed trace (timeout, pruned state...) is recorded as missing and the block
+// is synced without it
+func fetchTraces(data *blockData) error {
+	if !config.Config().Sync.Trace {
+		return nil
+	}
+
+	hashes := make([]string, 0)
+	for _, hash := range data.block.Transactions {
+		input := data.transactions[hash].Input
+		if input != "" && input != "0x" {
+			hashes = append(hashes, hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	frames, errs, err := c.Web3().Eth.TraceTransactions(hashes)
+	if err != nil {
+		log.Noticef("Eth.TraceTransactions,block:%d error:%s,traces missing", data.height, err.Error())
+		for _, hash := range hashes {
+			data.traceErrors[hash] = err.Error()
+		}
+		return nil
+	}
+	for i, hash := range hashes {
+		if errs[i] != nil {
+			log.Noticef("Eth.TraceTransactions,block:%d,hash:%s error:%s,trace missing", data.height, hash, errs[i].Error())
+			data.traceErrors[hash] = errs[i].Error()
+			continue
+		}
+		data.traces[hash] = frames[i]
+	}
+
+	log.Debugf("Trace %d transactions of block:%d success,%d missing", len(data.traces), data.height, len(data.traceErrors))
+	return nil
+}
+
+// flattenCalls append the calls below frame depth first, a call inside a failed one is reverted with it
+func flattenCalls(internals []model.InternalTx, frame *dto.CallFrame, depth int64, parentError string) []model.InternalTx {
+	for i := range frame.Calls {
+		call := &frame.Calls[i]
+
+		internal := model.InternalTx{}
+		internal.F_depth = depth
+		internal.F_type = strings.ToUpper(call.Type)
+		internal.F_from = strings.ToLower(call.From)
+		internal.F_to = strings.ToLower(call.To)
+		internal.F_value = "0"
+		if call.Value != nil {
+			internal.F_value = call.Value.String()
+		}
+		internal.F_error = call.Error
+		if internal.F_error == "" {
+			internal.F_error = parentError
+		}
+		if len(internal.F_error) > 512 {
+			internal.F_error = internal.F_error[:512]
+		}
+
+		internals = append(internals, internal)
+		internals = flattenCalls(internals, call, depth+1, internal.F_error)
+	}
+	return internals
+}
+
+// WriteInternalTxs write the calls of the traced transactions and record the ones whose trace failed
+func WriteInternalTxs(db *gorm.DB, chain_block dto.Block, transactions map[string]dto.TransactionResponse,
+	traces map[string]*dto.CallFrame, traceErrors map[string]string) error {
+	if !config.Config().Sync.Trace {
+		return nil
+	}
+
+	missings := make([]model.TraceMissing, 0, len(traceErrors))
+	for hash, traceError := range traceErrors {
+		if len(traceError) > 512 {
+			traceError = traceError[:512]
+		}
+		missings = append(missings, model.TraceMissing{F_block: chain_block.Number.Int64(), F_block_hash: chain_block.Hash,
+			F_tx_hash: hash, F_error: traceError})
+	}
+	err := model.ReplaceTraceMissings(db, chain_block.Hash, missings)
+	if err != nil {
+		log.Debugf("ReplaceTraceMissings,block:%d error:%s", chain_block.Number.Int64(), err.Error())
+		return err
+	}
+	if len(traces) == 0 {
+		return nil
+	}
+
+	//trace index follows the transaction order of the block
+	hashes := make([]string, 0, len(traces))
+	for hash := range traces {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return transactions[hashes[i]].TransactionIndex.Cmp(transactions[hashes[j]].TransactionIndex) < 0
+	})
+
+	databases_internals := make([]model.InternalTx, 0)
+	for _, hash := range hashes {
+		frame := traces[hash]
+		internals := flattenCalls(make([]model.InternalTx, 0), frame, 1, frame.Error)
+		for _, internal := range internals {
+			internal.F_block = chain_block.Number.Int64()
+			internal.F_block_hash = chain_block.Hash
+			internal.F_timestamp = chain_block.Timestamp.Int64()
+			internal.F_tx_hash = hash
+			internal.F_trace_index = int64(len(databases_internals))
+			databases_internals = append(databases_internals, internal)
+		}
+	}
+
+	err = model.CreateInternalTxs(db, databases_internals)
+	if err != nil {
+		log.Debugf("CreateInternalTxs,block:%d error:%s", chain_block.Number.Int64(), err.Error())
+		return err
+	}
+
+	log.Debugf("CreateInternalTxs success,block:%d,num:%d", chain_block.Number.Int64(), len(databases_internals))
+
+	return nil
+}
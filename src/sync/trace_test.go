@@ -0,0 +1,74 @@
+package sync
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go-web3/dto"
+)
+
+func TestFlattenCalls(t *testing.T) {
+	trace := `{"type":"CALL","from":"0xA","to":"0xB","value":"0x0","calls":[
+		{"type":"call","from":"0xB","to":"0xC","value":"0xde0b6b3a7640000","calls":[
+			{"type":"staticcall","from":"0xC","to":"0xD"}
+		]},
+		{"type":"CREATE","from":"0xB","to":"0xE","value":"0x1","error":"out of gas","calls":[
+			{"type":"CALL","from":"0xE","to":"0xF","value":"0x2"}
+		]},
+		{"type":"DELEGATECALL","from":"0xB","to":"0xG"}
+	]}`
+
+	frame := dto.CallFrame{}
+	if err := json.Unmarshal([]byte(trace), &frame); err != nil {
+		t.Fatalf("unmarshal trace failed: err=%q", err)
+	}
+
+	tests := []struct {
+		depth int64
+		typ   string
+		from  string
+		to    string
+		value string
+		err   string
+	}{
+		{1, "CALL", "0xb", "0xc", "1000000000000000000", ""},
+		{2, "STATICCALL", "0xc", "0xd", "0", ""},
+		{1, "CREATE", "0xb", "0xe", "1", "out of gas"},
+		{2, "CALL", "0xe", "0xf", "2", "out of gas"}, //reverted with its failed parent
+		{1, "DELEGATECALL", "0xb", "0xg", "0", ""},
+	}
+
+	internals := flattenCalls(nil, &frame, 1, frame.Error)
+	if len(internals) != len(tests) {
+		t.Fatalf("flattened %d calls, want %d: %+v", len(internals), len(tests), internals)
+	}
+	for i, test := range tests {
+		internal := internals[i]
+		if internal.F_depth != test.depth || internal.F_type != test.typ || internal.F_from != test.from ||
+			internal.F_to != test.to || internal.F_value != test.value || internal.F_error != test.err {
+			t.Errorf("call %d: got %+v, want %+v", i, internal, test)
+		}
+	}
+}
+
+func TestFlattenCallsFailedTransaction(t *testing.T) {
+	frame := dto.CallFrame{Error: "execution reverted", Calls: []dto.CallFrame{{Type: "CALL", From: "0xA", To: "0xB"}}}
+
+	internals := flattenCalls(nil, &frame, 1, frame.Error)
+	if len(internals) != 1 || internals[0].F_error != "execution reverted" {
+		t.Errorf("calls of a reverted transaction: %+v", internals)
+	}
+}
+
+func TestFlattenCallsLongError(t *testing.T) {
+	long := make([]byte, 600)
+	for i := range long {
+		long[i] = 'x'
+	}
+	frame := dto.CallFrame{Calls: []dto.CallFrame{{Type: "CALL", Error: string(long)}}}
+
+	internals := flattenCalls(nil, &frame, 1, "")
+	if len(internals) != 1 || len(internals[0].F_error) != 512 {
+		t.Errorf("error is not cut to the column size: %d", len(internals[0].F_error))
+	}
+}
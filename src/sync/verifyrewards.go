@@ -0,0 +1,180 @@
+package sync
+
+import (
+	"fmt"
+	. "github.com/EthereumHD/Scan/src/const"
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/jinzhu/gorm"
+	"qoobing.com/utillib.golang/log"
+)
+
+// VERIFYREWARDSBATCH the heights of t_block compared with the ledger at a time
+const VERIFYREWARDSBATCH = 1000
+
+// RewardReport is the differences found by VerifyRewards
+type RewardReport struct {
+	Blocks      int64 //blocks compared
+	BlockDiffs  int64 //blocks whose ledger entries disagree with t_block
+	Miners      int64 //miners compared
+	MinerDiffs  int64 //miners whose t_miner_reward disagrees with the ledger
+	BlocksFixed int64
+	MinersFixed int64
+}
+
+// VerifyRewards compare the reward ledger with the blocks of t_block, then t_miner_reward with the ledger.
+// With fix the ledger is settled for every block that differs and the totals are derived again, the first
+// run after upgrading credits the blocks synced before the ledger existed
+func VerifyRewards(fix bool) (report RewardReport, err error) {
+	db := c.Mysql()
+
+	max, err := (&model.Block{}).GetMaxBlocNumber(db)
+	if err != nil && err.Error() != DATA_NOT_EXIST {
+		return report, err
+	}
+
+	log.Noticef("VerifyRewards start,max height:%d,fix:%t", max, fix)
+	for from := int64(0); from <= max; from += VERIFYREWARDSBATCH {
+		to := from + VERIFYREWARDSBATCH - 1
+		if err = verifyBlockRewards(db, from, to, fix, &report); err != nil {
+			return report, fmt.Errorf("verify rewards of blocks:%d-%d error:%s", from, to, err.Error())
+		}
+	}
+
+	if err = verifyMinerRewards(db, fix, &report); err != nil {
+		return report, fmt.Errorf("verify miner rewards error:%s", err.Error())
+	}
+
+	log.Noticef("VerifyRewards finish,%+v", report)
+	return report, nil
+}
+
+// verifyBlockRewards compare the blocks of heights [from, to] with their ledger entries
+func verifyBlockRewards(db *gorm.DB, from, to int64, fix bool, report *RewardReport) error {
+	blocks, err := model.GetBlocksByHeight(db, from, to)
+	if err != nil {
+		return err
+	}
+	entries, err := model.GetRewardEntriesByHeight(db, from, to)
+	if err != nil {
+		return err
+	}
+
+	//the entries are in F_seq order, the last one of a hash tells whether it is credited
+	last := make(map[string]model.RewardEntry)
+	for _, entry := range entries {
+		last[entry.F_block_hash] = entry
+	}
+
+	diffs := make([]string, 0)
+	for _, block := range blocks {
+		report.Blocks++
+
+		entry, found := last[block.F_hash]
+		delete(last, block.F_hash)
+		credited := found && entry.F_sign > 0
+		switch {
+		case block.F_status == NORMAL && !credited:
+			log.Noticef("block:%d,hash:%s,miner:%s not credited", block.F_block, block.F_hash, block.F_miner)
+		case block.F_status != NORMAL && credited:
+			log.Noticef("block:%d,hash:%s,miner:%s forked but credited", block.F_block, block.F_hash, block.F_miner)
+		case credited && (!sameAmount(entry.F_reward, block.F_reward) || !sameAmount(entry.F_fees, block.F_fees)):
+			log.Noticef("block:%d,hash:%s,miner:%s credited reward:%s,fees:%s,block reward:%s,fees:%s", block.F_block,
+				block.F_hash, block.F_miner, entry.F_reward, entry.F_fees, block.F_reward, block.F_fees)
+		default:
+			continue
+		}
+		report.BlockDiffs++
+		diffs = append(diffs, block.F_hash)
+	}
+
+	//credited hashes t_block does not have
+	for hash, entry := range last {
+		if entry.F_sign > 0 {
+			log.Noticef("block:%d,hash:%s,miner:%s credited but not in t_block", entry.F_block, hash, entry.F_miner)
+			report.BlockDiffs++
+			diffs = append(diffs, hash)
+		}
+	}
+
+	if !fix {
+		return nil
+	}
+	for _, hash := range diffs {
+		err = model.InTransaction(db, func(tx *gorm.DB) error {
+			//read again in the transaction, the syncer may have settled it meanwhile
+			block, err := (&model.Block{}).FindBlockByHash(tx, hash)
+			if err != nil {
+				if err.Error() != DATA_NOT_EXIST {
+					return err
+				}
+				block = model.Block{F_hash: hash, F_status: FORK}
+			}
+
+			changed, err := settleBlockReward(tx, block)
+			if changed {
+				report.BlocksFixed++
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyMinerRewards compare t_miner_reward with the sums of the ledger
+func verifyMinerRewards(db *gorm.DB, fix bool, report *RewardReport) error {
+	totals, err := model.GetRewardTotals(db)
+	if err != nil {
+		return err
+	}
+
+	rewards, err := (&model.MinerReward{}).GetAddrList(db)
+	if err != nil && err.Error() != DATA_NOT_EXIST {
+		return err
+	}
+	stored := make(map[string]model.MinerReward)
+	for _, reward := range rewards {
+		stored[reward.F_miner] = reward
+	}
+
+	diffs := make([]string, 0)
+	for _, total := range totals {
+		report.Miners++
+
+		reward, found := stored[total.F_miner]
+		delete(stored, total.F_miner)
+		if found && sameAmount(reward.F_total_reward, total.Reward) && sameAmount(reward.F_total_fees, total.Fees) {
+			continue
+		}
+		log.Noticef("miner:%s total reward:%s,fees:%s,ledger reward:%s,fees:%s", total.F_miner,
+			reward.F_total_reward, reward.F_total_fees, total.Reward, total.Fees)
+		report.MinerDiffs++
+		diffs = append(diffs, total.F_miner)
+	}
+
+	//miners without any ledger entry should have nothing
+	for miner, reward := range stored {
+		report.Miners++
+		if sameAmount(reward.F_total_reward, "0") && sameAmount(reward.F_total_fees, "0") {
+			continue
+		}
+		log.Noticef("miner:%s total reward:%s,fees:%s,no ledger entry", miner, reward.F_total_reward, reward.F_total_fees)
+		report.MinerDiffs++
+		diffs = append(diffs, miner)
+	}
+
+	if !fix {
+		return nil
+	}
+	for _, miner := range diffs {
+		if err = model.RefreshMinerReward(db, miner); err != nil {
+			return err
+		}
+		report.MinersFixed++
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/EthereumHD/Scan/src/model"
+	"github.com/EthereumHD/Scan/src/sync"
+	"qoobing.com/utillib.golang/log"
+)
+
+// verifyRewards is `scan verify-rewards [--dry-run]`, it reconciles the reward ledger and the miner totals with t_block
+func verifyRewards(args []string) int {
+	flags := flag.NewFlagSet("verify-rewards", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report the differences without fixing them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	model.InitDatabase()
+
+	report, err := sync.VerifyRewards(!*dryRun)
+	if err != nil {
+		log.Fatalf("VerifyRewards error:%s", err.Error())
+		fmt.Fprintln(os.Stderr, "verify-rewards:", err.Error())
+		return 1
+	}
+
+	fmt.Printf("blocks:%d,differ:%d,fixed:%d\n", report.Blocks, report.BlockDiffs, report.BlocksFixed)
+	fmt.Printf("miners:%d,differ:%d,fixed:%d\n", report.Miners, report.MinerDiffs, report.MinersFixed)
+	if *dryRun && (report.BlockDiffs > 0 || report.MinerDiffs > 0) {
+		return 1
+	}
+
+	return 0
+}